@@ -0,0 +1,283 @@
+// Package analysis statically analyses the Go code of a Golte project: the
+// routes it registers and the components it renders.
+package analysis
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// Import paths of the flavors.
+const (
+	GolteImport    = "github.com/nichady/golte"
+	SveltigoImport = "github.com/HazelnutParadise/sveltigo"
+)
+
+// Position is a location in the project's source.
+type Position struct {
+	// File is relative to the project.
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	Column int    `json:"column"`
+}
+
+func (p Position) String() string {
+	if p.Line == 0 {
+		return p.File
+	}
+	return fmt.Sprintf("%s:%d", p.File, p.Line)
+}
+
+// Project is the parsed Go code of a project.
+type Project struct {
+	Path     string
+	Fset     *token.FileSet
+	Packages []*packages.Package
+}
+
+// Load parses the packages matching patterns, ./... by default, in
+// projectPath. The code is not type-checked, so it can be analysed even when
+// it does not compile or its dependencies are not downloaded; constants and
+// functions are resolved by name within the loaded packages.
+func Load(projectPath string, patterns ...string) (*Project, error) {
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+	fset := token.NewFileSet()
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedSyntax,
+		Dir:  projectPath,
+		Fset: fset,
+	}
+	pkgs, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load packages: %v", err)
+	}
+	return &Project{Path: projectPath, Fset: fset, Packages: pkgs}, nil
+}
+
+// Position returns the position of pos relative to the project.
+func (p *Project) Position(pos token.Pos) Position {
+	position := p.Fset.Position(pos)
+	return Position{File: p.relative(position.Filename), Line: position.Line, Column: position.Column}
+}
+
+// importName returns the name the import is referred to by in its file.
+func importName(spec *ast.ImportSpec) string {
+	if spec.Name != nil {
+		return spec.Name.Name
+	}
+	importPath, _ := strconv.Unquote(spec.Path.Value)
+	return packageName(importPath)
+}
+
+// packageName guesses the package name of an import path, skipping a /vN suffix.
+func packageName(importPath string) string {
+	name := filepath.Base(importPath)
+	if len(name) > 1 && name[0] == 'v' {
+		if _, err := strconv.Atoi(name[1:]); err == nil {
+			name = filepath.Base(filepath.Dir(importPath))
+		}
+	}
+	return name
+}
+
+// source is a file of a loaded package, which names are resolved in.
+type source struct {
+	project *Project
+	pkg     *packages.Package
+	file    *ast.File
+}
+
+// importPathOf returns the import path the package name refers to in the file, or "".
+func (s source) importPathOf(name string) string {
+	for _, spec := range s.file.Imports {
+		if importName(spec) == name {
+			importPath, _ := strconv.Unquote(spec.Path.Value)
+			return importPath
+		}
+	}
+	return ""
+}
+
+// componentArgs maps the Golte and Sveltigo functions taking a component name
+// to the index of that argument.
+var componentArgs = map[string]int{
+	"RenderPage": 2,
+	"Page":       0,
+	"AddLayout":  1,
+	"Layout":     0,
+}
+
+// componentArg returns the function and the component name argument if call
+// calls one of componentArgs of Golte or Sveltigo.
+func (s source) componentArg(call *ast.CallExpr) (string, ast.Expr, bool) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return "", nil, false
+	}
+	index, ok := componentArgs[sel.Sel.Name]
+	if !ok || len(call.Args) <= index {
+		return "", nil, false
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	if !ok || pkg.Obj != nil {
+		return "", nil, false
+	}
+	if importPath := s.importPathOf(pkg.Name); importPath != GolteImport && importPath != SveltigoImport {
+		return "", nil, false
+	}
+	return sel.Sel.Name, call.Args[index], true
+}
+
+// stringValue returns the value of a string literal, a string constant or a
+// concatenation of them.
+func (s source) stringValue(expr ast.Expr) (string, bool) {
+	switch expr := expr.(type) {
+	case *ast.BasicLit:
+		if expr.Kind != token.STRING {
+			return "", false
+		}
+		value, err := strconv.Unquote(expr.Value)
+		return value, err == nil
+	case *ast.ParenExpr:
+		return s.stringValue(expr.X)
+	case *ast.BinaryExpr:
+		if expr.Op != token.ADD {
+			return "", false
+		}
+		x, ok := s.stringValue(expr.X)
+		if !ok {
+			return "", false
+		}
+		y, ok := s.stringValue(expr.Y)
+		if !ok {
+			return "", false
+		}
+		return x + y, true
+	case *ast.Ident, *ast.SelectorExpr:
+		if value, in, ok := s.lookup(expr, token.CONST); ok {
+			if spec := value.(*ast.ValueSpec); len(spec.Values) == len(spec.Names) {
+				for i, name := range spec.Names {
+					if name.Name == identOf(expr).Name {
+						return in.stringValue(spec.Values[i])
+					}
+				}
+			}
+		}
+	}
+	return "", false
+}
+
+// funcDecl returns the declaration of the function expr, a name or a
+// qualified name, refers to and the file it is in.
+func (s source) funcDecl(expr ast.Expr) (*ast.FuncDecl, source) {
+	if decl, in, ok := s.lookup(expr, token.FUNC); ok {
+		return decl.(*ast.FuncDecl), in
+	}
+	return nil, source{}
+}
+
+// lookup finds the declaration expr refers to: a constant ValueSpec for tok
+// CONST, a FuncDecl for tok FUNC or a TypeSpec for tok TYPE. Names in other
+// files are looked up in the package-level declarations.
+func (s source) lookup(expr ast.Expr, tok token.Token) (ast.Node, source, bool) {
+	pkg := s.pkg
+	var name string
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		// 同一個檔案中的宣告已由 parser 解析，其他檔案的名稱在套件中尋找
+		if expr.Obj != nil {
+			switch decl := expr.Obj.Decl.(type) {
+			case *ast.ValueSpec:
+				if tok == token.CONST && expr.Obj.Kind == ast.Con {
+					return decl, s, true
+				}
+			case *ast.FuncDecl:
+				if tok == token.FUNC && decl.Recv == nil && decl.Body != nil {
+					return decl, s, true
+				}
+			case *ast.TypeSpec:
+				if tok == token.TYPE {
+					return decl, s, true
+				}
+			}
+			return nil, source{}, false
+		}
+		name = expr.Name
+	case *ast.SelectorExpr:
+		x, ok := expr.X.(*ast.Ident)
+		if !ok || x.Obj != nil {
+			return nil, source{}, false
+		}
+		pkg = s.project.packageOf(s.importPathOf(x.Name))
+		name = expr.Sel.Name
+	}
+	if pkg == nil || name == "" {
+		return nil, source{}, false
+	}
+	for _, file := range pkg.Syntax {
+		in := source{project: s.project, pkg: pkg, file: file}
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				if tok == token.FUNC && decl.Recv == nil && decl.Body != nil && decl.Name.Name == name {
+					return decl, in, true
+				}
+			case *ast.GenDecl:
+				if tok != decl.Tok {
+					continue
+				}
+				for _, spec := range decl.Specs {
+					switch spec := spec.(type) {
+					case *ast.ValueSpec:
+						if tok == token.CONST && slices.ContainsFunc(spec.Names, func(n *ast.Ident) bool { return n.Name == name }) {
+							return spec, in, true
+						}
+					case *ast.TypeSpec:
+						if spec.Name.Name == name {
+							return spec, in, true
+						}
+					}
+				}
+			}
+		}
+	}
+	return nil, source{}, false
+}
+
+// packageOf returns the loaded package with importPath, or nil.
+func (p *Project) packageOf(importPath string) *packages.Package {
+	for _, pkg := range p.Packages {
+		if pkg.PkgPath == importPath {
+			return pkg
+		}
+	}
+	return nil
+}
+
+// identOf returns the name expr, an identifier or qualified identifier, ends with.
+func identOf(expr ast.Expr) *ast.Ident {
+	if sel, ok := expr.(*ast.SelectorExpr); ok {
+		return sel.Sel
+	}
+	return expr.(*ast.Ident)
+}
+
+// ComponentFile returns the Svelte file of a component like "pages/About" under srcDir.
+func ComponentFile(srcDir, component string) string {
+	return filepath.Join(srcDir, filepath.FromSlash(component)+".svelte")
+}
+
+// ComponentExists reports whether the Svelte file of component exists under srcDir.
+func ComponentExists(srcDir, component string) bool {
+	info, err := os.Stat(ComponentFile(srcDir, component))
+	return err == nil && !info.IsDir()
+}
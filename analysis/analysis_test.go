@@ -0,0 +1,29 @@
+package analysis
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// loadProject writes files to a new module and loads it. The imported
+// packages are not downloaded; the analysis only needs the project's syntax.
+func loadProject(t *testing.T, files map[string]string) *Project {
+	t.Helper()
+	root := t.TempDir()
+	files["go.mod"] = "module example.com/app\n\ngo 1.22\n"
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	project, err := Load(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return project
+}
@@ -0,0 +1,284 @@
+package analysis
+
+import (
+	"fmt"
+	"go/ast"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// Reference is a component name passed to RenderPage, Page, AddLayout or Layout.
+type Reference struct {
+	// Func is the function the component is passed to.
+	Func string
+	// Component is the component name like "pages/App", or "" if it is not a constant.
+	Component string
+	Position  Position
+}
+
+// References returns the component names passed to Golte and Sveltigo in the
+// loaded packages, in source order.
+func (p *Project) References() []Reference {
+	var refs []Reference
+	for _, pkg := range p.Packages {
+		for _, file := range pkg.Syntax {
+			s := source{project: p, pkg: pkg, file: file}
+			ast.Inspect(file, func(n ast.Node) bool {
+				call, ok := n.(*ast.CallExpr)
+				if !ok {
+					return true
+				}
+				if fn, arg, ok := s.componentArg(call); ok {
+					component, _ := s.stringValue(arg)
+					refs = append(refs, Reference{Func: fn, Component: component, Position: p.Position(arg.Pos())})
+				}
+				return true
+			})
+		}
+	}
+	return refs
+}
+
+// Severity is how serious an Issue is.
+type Severity string
+
+const (
+	// SeverityError is a component that does not exist and fails at runtime.
+	SeverityError Severity = "error"
+	// SeverityWarning is a problem that does not break the app, like an unused page.
+	SeverityWarning Severity = "warning"
+)
+
+// Issue is a problem found by Check.
+type Issue struct {
+	Severity Severity `json:"severity"`
+	Position Position `json:"position"`
+	Message  string   `json:"message"`
+}
+
+func (i Issue) String() string {
+	return fmt.Sprintf("%s: %s: %s", i.Position, i.Severity, i.Message)
+}
+
+// Check cross-references the component names passed to Golte and Sveltigo with
+// the Svelte files under srcDir. Packages that fail to load or parse and
+// components without a file are errors; pages
+// under pages/ that are never rendered are warnings. The props passed to each
+// page are compared with the props it declares, see checkProps. Issues are
+// sorted by position.
+func (p *Project) Check(srcDir string) ([]Issue, error) {
+	issues := p.loadIssues()
+	used := map[string]bool{}
+	var dynamic *Reference
+	refs := p.References()
+	for i, ref := range refs {
+		if ref.Component == "" {
+			if dynamic == nil {
+				dynamic = &refs[i]
+			}
+			continue
+		}
+		used[ref.Component] = true
+		if !ComponentExists(srcDir, ref.Component) {
+			issues = append(issues, Issue{
+				Severity: SeverityError,
+				Position: ref.Position,
+				Message:  fmt.Sprintf("%s(%q): %s does not exist", ref.Func, ref.Component, p.relative(ComponentFile(srcDir, ref.Component))),
+			})
+		}
+	}
+
+	// 元件名稱不是常數時無法判斷哪些頁面沒有被使用
+	if dynamic != nil {
+		issues = append(issues, Issue{
+			Severity: SeverityWarning,
+			Position: dynamic.Position,
+			Message:  fmt.Sprintf("the component passed to %s is not a constant, unused pages are not reported", dynamic.Func),
+		})
+	} else {
+		pages, err := svelteFiles(srcDir, "pages")
+		if err != nil {
+			return nil, err
+		}
+		for _, page := range pages {
+			if !used[page] {
+				issues = append(issues, Issue{
+					Severity: SeverityWarning,
+					Position: Position{File: p.relative(ComponentFile(srcDir, page))},
+					Message:  fmt.Sprintf("page %q is never rendered", page),
+				})
+			}
+		}
+	}
+
+	propIssues, err := p.checkProps(srcDir)
+	if err != nil {
+		return nil, err
+	}
+	issues = append(issues, propIssues...)
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		a, b := issues[i].Position, issues[j].Position
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Line < b.Line
+	})
+	return issues, nil
+}
+
+// loadIssues returns the errors of loading and parsing the packages, which
+// may hide the components they render. Only the first syntax error of each
+// file is reported.
+func (p *Project) loadIssues() []Issue {
+	var issues []Issue
+	seen := map[string]bool{}
+	for _, pkg := range p.Packages {
+		for _, e := range pkg.Errors {
+			position := p.errorPosition(e.Pos)
+			if position.File == "" {
+				position.File = pkg.PkgPath
+			}
+			key := e.Error()
+			if e.Kind == packages.ParseError {
+				key = position.File
+			}
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			issues = append(issues, Issue{Severity: SeverityError, Position: position, Message: e.Msg})
+		}
+	}
+	return issues
+}
+
+// errorPosition converts the "file:line:column" position of a packages.Error.
+func (p *Project) errorPosition(pos string) Position {
+	var numbers []int
+	for len(numbers) < 2 {
+		i := strings.LastIndex(pos, ":")
+		if i < 0 {
+			break
+		}
+		n, err := strconv.Atoi(pos[i+1:])
+		if err != nil {
+			break
+		}
+		numbers = append([]int{n}, numbers...)
+		pos = pos[:i]
+	}
+	var position Position
+	if pos != "" && pos != "-" {
+		position.File = p.relative(pos)
+	}
+	if len(numbers) > 0 {
+		position.Line = numbers[0]
+	}
+	if len(numbers) > 1 {
+		position.Column = numbers[1]
+	}
+	return position
+}
+
+// svelteFiles returns the components like "pages/App" of the Svelte files in
+// dir under srcDir.
+func svelteFiles(srcDir, dir string) ([]string, error) {
+	var components []string
+	err := filepath.WalkDir(filepath.Join(srcDir, dir), func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(p, ".svelte") {
+			return nil
+		}
+		rel, err := filepath.Rel(srcDir, p)
+		if err != nil {
+			return err
+		}
+		components = append(components, strings.TrimSuffix(path.Clean(filepath.ToSlash(rel)), ".svelte"))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the Svelte files in %s: %v", dir, err)
+	}
+	return components, nil
+}
+
+// relative returns file relative to the project if possible.
+func (p *Project) relative(file string) string {
+	if rel, err := filepath.Rel(p.Path, file); err == nil {
+		return filepath.ToSlash(rel)
+	}
+	return file
+}
+
+// primitiveTypes are the TypeScript types checkProps compares.
+var primitiveTypes = []string{"string", "number", "boolean"}
+
+// checkProps compares the props the Go code passes to each page with the props
+// the page declares. A declared prop without default that is never passed is an
+// error; props that are not declared, only passed at some call sites or have a
+// different primitive type are warnings. Pages whose props cannot be determined
+// are skipped.
+func (p *Project) checkProps(srcDir string) ([]Issue, error) {
+	var issues []Issue
+	for _, page := range p.PageProps() {
+		if page.Unknown || !ComponentExists(srcDir, page.Component) {
+			continue
+		}
+		file := ComponentFile(srcDir, page.Component)
+		declared, rest, err := SvelteProps(file)
+		if err != nil {
+			return nil, err
+		}
+		svelteFile := p.relative(file)
+
+		for _, prop := range page.Props {
+			if !rest && !slices.ContainsFunc(declared, func(d SvelteProp) bool { return d.Name == prop.Name }) {
+				issues = append(issues, Issue{
+					Severity: SeverityWarning,
+					Position: prop.Position,
+					Message:  fmt.Sprintf("prop %q passed to %s is not declared in %s", prop.Name, page.Component, svelteFile),
+				})
+			}
+		}
+		for _, d := range declared {
+			position := Position{File: svelteFile, Line: d.Line}
+			i := slices.IndexFunc(page.Props, func(prop Prop) bool { return prop.Name == d.Name })
+			switch {
+			case i < 0 && !d.HasDefault:
+				issues = append(issues, Issue{
+					Severity: SeverityError,
+					Position: position,
+					Message:  fmt.Sprintf("prop %q of %s has no default and is not passed by the Go code", d.Name, page.Component),
+				})
+			case i < 0:
+			case page.Props[i].Optional && !d.HasDefault:
+				issues = append(issues, Issue{
+					Severity: SeverityWarning,
+					Position: position,
+					Message:  fmt.Sprintf("prop %q of %s has no default but is not always passed by the Go code", d.Name, page.Component),
+				})
+			case slices.Contains(primitiveTypes, d.Type) && slices.Contains(primitiveTypes, page.Props[i].Type) && d.Type != page.Props[i].Type:
+				issues = append(issues, Issue{
+					Severity: SeverityWarning,
+					Position: position,
+					Message:  fmt.Sprintf("prop %q of %s is declared as %s, but the Go code passes a %s (%s)", d.Name, page.Component, d.Type, page.Props[i].Type, page.Props[i].Position),
+				})
+			}
+		}
+	}
+	return issues, nil
+}
@@ -0,0 +1,139 @@
+package analysis
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	const header = `package main
+
+import (
+	"net/http"
+
+	"github.com/nichady/golte"
+)
+
+`
+	tests := []struct {
+		name  string
+		files map[string]string
+		// want are the issues formatted by Issue.String.
+		want []string
+	}{
+		{
+			name: "no problems",
+			files: map[string]string{
+				"main.go": header + `func main() {
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		golte.AddLayout(r, "layouts/Main", nil)
+		golte.RenderPage(w, r, "pages/App", nil)
+	})
+}
+`,
+				"web/pages/App.svelte":    "<h1>App</h1>\n",
+				"web/layouts/Main.svelte": "<slot />\n",
+			},
+		},
+		{
+			name: "missing components",
+			files: map[string]string{
+				"main.go": header + `const aboutPage = "pages/About"
+
+func main() {
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		golte.AddLayout(r, "layouts/Missing", nil)
+		golte.RenderPage(w, r, "pages/App", nil)
+	})
+	http.HandleFunc("/about", func(w http.ResponseWriter, r *http.Request) {
+		golte.RenderPage(w, r, aboutPage, nil)
+	})
+}
+`,
+				"web/pages/App.svelte": "<h1>App</h1>\n",
+			},
+			want: []string{
+				`main.go:13: error: AddLayout("layouts/Missing"): web/layouts/Missing.svelte does not exist`,
+				`main.go:17: error: RenderPage("pages/About"): web/pages/About.svelte does not exist`,
+			},
+		},
+		{
+			name: "unused pages",
+			files: map[string]string{
+				"main.go": header + `func main() {
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		golte.RenderPage(w, r, "pages/App", nil)
+	})
+}
+`,
+				"web/pages/App.svelte":          "<h1>App</h1>\n",
+				"web/pages/Old.svelte":          "<h1>Old</h1>\n",
+				"web/pages/admin/Users.svelte":  "<h1>Users</h1>\n",
+				"web/components/Button.svelte":  "<button />\n",
+				"web/pages/admin/notes.txt":     "not a page\n",
+				"web/layouts/Unused.svelte":     "<slot />\n",
+				"web/pages/App.props.d.ts":      "export interface Props {}\n",
+				"web/pages/admin/Users.test.ts": "\n",
+			},
+			want: []string{
+				`web/pages/Old.svelte: warning: page "pages/Old" is never rendered`,
+				`web/pages/admin/Users.svelte: warning: page "pages/admin/Users" is never rendered`,
+			},
+		},
+		{
+			name: "syntax error",
+			files: map[string]string{
+				"main.go": header + `func main() {
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		golte.RenderPage(w, r, "pages/App", nil)
+	})
+}
+`,
+				"handlers/broken.go":   "package handlers\n\nfunc Broken( {\n",
+				"web/pages/App.svelte": "<h1>App</h1>\n",
+			},
+			want: []string{`handlers/broken.go:3: error: expected ')', found '{'`},
+		},
+		{
+			name: "import cycle",
+			files: map[string]string{
+				"main.go": "package main\n\nimport _ \"example.com/app/a\"\n\nfunc main() {}\n",
+				"a/a.go":  "package a\n\nimport _ \"example.com/app/b\"\n",
+				"b/b.go":  "package b\n\nimport _ \"example.com/app/a\"\n",
+			},
+			want: []string{"example.com/app/a: error: import cycle not allowed: import stack: [example.com/app example.com/app/a example.com/app/b example.com/app/a]"},
+		},
+		{
+			name: "dynamic component",
+			files: map[string]string{
+				"main.go": header + `func main() {
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		golte.RenderPage(w, r, "pages/"+r.URL.Query().Get("page"), nil)
+	})
+}
+`,
+				"web/pages/App.svelte": "<h1>App</h1>\n",
+			},
+			want: []string{
+				`main.go:11: warning: the component passed to RenderPage is not a constant, unused pages are not reported`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			project := loadProject(t, tt.files)
+			issues, err := project.Check(filepath.Join(project.Path, "web"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, issue := range issues {
+				got = append(got, issue.String())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Check() =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,589 @@
+package analysis
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PropsDirective annotates a struct as the props of a page:
+//
+//	//golte:props pages/App
+//	type AppProps struct { ... }
+const PropsDirective = "//golte:props"
+
+// Prop is a prop a page receives from Go.
+type Prop struct {
+	Name string `json:"name"`
+	// Type is the TypeScript type of the prop.
+	Type string `json:"type"`
+	// Optional is set for omitempty fields and keys not passed at every call site.
+	Optional bool     `json:"optional,omitempty"`
+	Position Position `json:"position"`
+}
+
+// Interface is a TypeScript interface generated for a Go struct.
+type Interface struct {
+	Name  string `json:"name"`
+	Props []Prop `json:"props"`
+}
+
+// PageProps are the props the Go code passes to a page.
+type PageProps struct {
+	Component string `json:"component"`
+	Props     []Prop `json:"props"`
+	// Interfaces are the structs Props refer to, in the order they were found.
+	Interfaces []Interface `json:"interfaces,omitempty"`
+	// Sources are the annotated struct or the RenderPage call sites.
+	Sources []Position `json:"sources"`
+	// Unknown is set if the props passed at one of the call sites cannot be
+	// determined statically, e.g. because the map is built elsewhere.
+	Unknown bool `json:"unknown,omitempty"`
+}
+
+// PageProps returns the props of every page rendered by the loaded packages,
+// sorted by component. A struct annotated with PropsDirective takes precedence;
+// otherwise the map or struct literals passed to RenderPage are merged.
+func (p *Project) PageProps() []PageProps {
+	pages := map[string]*PageProps{}
+	converters := map[string]*tsConverter{}
+	page := func(component string) (*PageProps, *tsConverter) {
+		if pages[component] == nil {
+			pages[component] = &PageProps{Component: component}
+			converters[component] = &tsConverter{}
+		}
+		return pages[component], converters[component]
+	}
+
+	// 先找出標註的 struct，它們決定頁面的 props
+	annotated := map[string]bool{}
+	for _, pkg := range p.Packages {
+		for _, file := range pkg.Syntax {
+			s := source{project: p, pkg: pkg, file: file}
+			for _, decl := range file.Decls {
+				gen, ok := decl.(*ast.GenDecl)
+				if !ok || gen.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range gen.Specs {
+					ts := spec.(*ast.TypeSpec)
+					st, ok := ts.Type.(*ast.StructType)
+					if !ok {
+						continue
+					}
+					for _, component := range propsDirectives(gen.Doc, ts.Doc) {
+						pp, c := page(component)
+						annotated[component] = true
+						pp.Props = c.structProps(s, st)
+						pp.Sources = append(pp.Sources, p.Position(ts.Pos()))
+					}
+				}
+			}
+		}
+	}
+
+	// sites counts the call sites with known props of each page
+	sites := map[string]int{}
+	for _, pkg := range p.Packages {
+		for _, file := range pkg.Syntax {
+			s := source{project: p, pkg: pkg, file: file}
+			ast.Inspect(file, func(n ast.Node) bool {
+				call, ok := n.(*ast.CallExpr)
+				if !ok {
+					return true
+				}
+				fn, arg, ok := s.componentArg(call)
+				if !ok || fn != "RenderPage" || len(call.Args) < 4 {
+					return true
+				}
+				component, ok := s.stringValue(arg)
+				if !ok || annotated[component] {
+					return true
+				}
+				pp, c := page(component)
+				pp.Sources = append(pp.Sources, p.Position(call.Pos()))
+				props, known := c.propsOf(s, call.Args[3])
+				switch {
+				case !known:
+					pp.Unknown = true
+				case sites[component] == 0:
+					pp.Props = props
+				default:
+					pp.Props = mergeProps(pp.Props, props)
+				}
+				if known {
+					sites[component]++
+				}
+				return true
+			})
+		}
+	}
+
+	var result []PageProps
+	for component, pp := range pages {
+		pp.Interfaces = converters[component].interfaces
+		result = append(result, *pp)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Component < result[j].Component })
+	return result
+}
+
+// propsDirectives returns the components named by PropsDirective in the comments.
+func propsDirectives(docs ...*ast.CommentGroup) []string {
+	var components []string
+	for _, doc := range docs {
+		if doc == nil {
+			continue
+		}
+		for _, comment := range doc.List {
+			if rest, ok := strings.CutPrefix(comment.Text, PropsDirective); ok {
+				for _, component := range strings.Fields(rest) {
+					if !slices.Contains(components, component) {
+						components = append(components, component)
+					}
+				}
+			}
+		}
+	}
+	return components
+}
+
+// mergeProps merges the props of two call sites: keys missing at one of them
+// become optional and different types become a union.
+func mergeProps(a, b []Prop) []Prop {
+	merged := slices.Clone(a)
+	for i := range merged {
+		j := slices.IndexFunc(b, func(prop Prop) bool { return prop.Name == merged[i].Name })
+		if j < 0 {
+			merged[i].Optional = true
+			continue
+		}
+		merged[i].Optional = merged[i].Optional || b[j].Optional
+		merged[i].Type = unionType(merged[i].Type, b[j].Type)
+	}
+	for _, prop := range b {
+		if !slices.ContainsFunc(a, func(other Prop) bool { return other.Name == prop.Name }) {
+			prop.Optional = true
+			merged = append(merged, prop)
+		}
+	}
+	return merged
+}
+
+// unionType returns the union of two TypeScript types.
+func unionType(a, b string) string {
+	if a == b || b == "unknown" {
+		return a
+	}
+	if a == "unknown" {
+		return b
+	}
+	parts := strings.Split(a, " | ")
+	for _, part := range strings.Split(b, " | ") {
+		if !slices.Contains(parts, part) {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, " | ")
+}
+
+// tsConverter converts Go types and values to TypeScript types. Named structs
+// become interfaces.
+type tsConverter struct {
+	interfaces []Interface
+	// structs maps the declarations of the converted structs to their interface name.
+	structs map[*ast.TypeSpec]string
+}
+
+// propsOf returns the props of the props argument of RenderPage. known is false
+// if they cannot be determined.
+func (c *tsConverter) propsOf(s source, expr ast.Expr) (props []Prop, known bool) {
+	if unary, ok := expr.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+		expr = unary.X
+	}
+	if id, ok := expr.(*ast.Ident); ok && id.Name == "nil" && id.Obj == nil {
+		return nil, true
+	}
+	if lit, ok := expr.(*ast.CompositeLit); ok {
+		if s.isMapType(lit.Type) {
+			for _, elt := range lit.Elts {
+				kv, ok := elt.(*ast.KeyValueExpr)
+				if !ok {
+					return nil, false
+				}
+				key, ok := s.stringValue(kv.Key)
+				if !ok {
+					return nil, false
+				}
+				props = append(props, Prop{Name: key, Type: c.valueType(s, kv.Value), Position: s.project.Position(kv.Pos())})
+			}
+			return props, true
+		}
+	}
+	// 例如 toMap(PageProps{...})，使用其中的 struct literal
+	var found *ast.StructType
+	var in source
+	ast.Inspect(expr, func(n ast.Node) bool {
+		if lit, ok := n.(*ast.CompositeLit); ok && found == nil && lit.Type != nil {
+			if spec, specSource, ok := s.typeSpec(lit.Type); ok {
+				if st, ok := spec.Type.(*ast.StructType); ok {
+					found, in = st, specSource
+				}
+			}
+		}
+		return found == nil
+	})
+	if found != nil {
+		return c.structProps(in, found), true
+	}
+	return nil, false
+}
+
+// routerMaps are the map[string]any types of the router packages, like gin.H.
+var routerMaps = map[string]string{
+	"github.com/gin-gonic/gin":    "H",
+	"github.com/labstack/echo/v4": "Map",
+	"github.com/gofiber/fiber/v2": "Map",
+}
+
+// isMapType reports whether typ is a map type, a map type declared in the
+// project or one of routerMaps.
+func (s source) isMapType(typ ast.Expr) bool {
+	switch t := typ.(type) {
+	case *ast.MapType:
+		return true
+	case *ast.SelectorExpr:
+		if x, ok := t.X.(*ast.Ident); ok && x.Obj == nil && routerMaps[s.importPathOf(x.Name)] == t.Sel.Name {
+			return true
+		}
+	}
+	if spec, in, ok := s.typeSpec(typ); ok {
+		return in.isMapType(spec.Type)
+	}
+	return false
+}
+
+// structProps returns the props of a struct, as encoding/json encodes it.
+func (c *tsConverter) structProps(s source, st *ast.StructType) []Prop {
+	var props []Prop
+	for _, field := range st.Fields.List {
+		name, omitEmpty, skip := jsonTag(field.Tag)
+		if skip {
+			continue
+		}
+		if len(field.Names) == 0 {
+			// 嵌入的 struct 欄位會被展開
+			typ := field.Type
+			if star, ok := typ.(*ast.StarExpr); ok {
+				typ = star.X
+			}
+			if spec, in, ok := s.typeSpec(typ); ok && name == "" {
+				if embedded, ok := spec.Type.(*ast.StructType); ok {
+					props = append(props, c.structProps(in, embedded)...)
+					continue
+				}
+			}
+			if id := embeddedName(typ); id != "" && ast.IsExported(id) {
+				props = append(props, c.fieldProp(s, field, firstNonEmpty(name, id), omitEmpty))
+			}
+			continue
+		}
+		for _, id := range field.Names {
+			if ast.IsExported(id.Name) {
+				props = append(props, c.fieldProp(s, field, firstNonEmpty(name, id.Name), omitEmpty))
+			}
+		}
+	}
+	return props
+}
+
+func (c *tsConverter) fieldProp(s source, field *ast.Field, name string, optional bool) Prop {
+	return Prop{Name: name, Type: c.goType(s, field.Type), Optional: optional, Position: s.project.Position(field.Pos())}
+}
+
+// jsonTag parses the json tag of a struct field.
+func jsonTag(tag *ast.BasicLit) (name string, omitEmpty, skip bool) {
+	if tag == nil {
+		return "", false, false
+	}
+	value, _ := strconv.Unquote(tag.Value)
+	name, options, _ := strings.Cut(reflect.StructTag(value).Get("json"), ",")
+	if name == "-" && options == "" {
+		return "", false, true
+	}
+	return name, slices.Contains(strings.Split(options, ","), "omitempty"), false
+}
+
+// embeddedName returns the field name of an embedded type, or "".
+func embeddedName(typ ast.Expr) string {
+	switch t := typ.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.SelectorExpr:
+		return t.Sel.Name
+	}
+	return ""
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// goType returns the TypeScript type of the JSON encoding of a Go type.
+func (c *tsConverter) goType(s source, expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.ParenExpr:
+		return c.goType(s, t.X)
+	case *ast.StarExpr:
+		return unionType(c.goType(s, t.X), "null")
+	case *ast.ArrayType:
+		if id, ok := t.Elt.(*ast.Ident); ok && id.Name == "byte" && t.Len == nil {
+			// []byte 會被編碼成 base64 字串
+			return "string"
+		}
+		return arrayType(c.goType(s, t.Elt))
+	case *ast.MapType:
+		return "Record<string, " + c.goType(s, t.Value) + ">"
+	case *ast.InterfaceType:
+		return "unknown"
+	case *ast.StructType:
+		return inlineType(c.structProps(s, t))
+	case *ast.Ident:
+		if t.Obj == nil {
+			switch t.Name {
+			case "string":
+				return "string"
+			case "bool":
+				return "boolean"
+			case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64",
+				"uintptr", "float32", "float64", "byte", "rune":
+				return "number"
+			case "any", "error":
+				return "unknown"
+			}
+		}
+	case *ast.SelectorExpr:
+		if x, ok := t.X.(*ast.Ident); ok && s.importPathOf(x.Name) == "time" && t.Sel.Name == "Time" {
+			return "string"
+		}
+		if x, ok := t.X.(*ast.Ident); ok && s.importPathOf(x.Name) == "encoding/json" && t.Sel.Name == "RawMessage" {
+			return "unknown"
+		}
+		if x, ok := t.X.(*ast.Ident); ok && routerMaps[s.importPathOf(x.Name)] == t.Sel.Name {
+			return "Record<string, unknown>"
+		}
+	}
+	if spec, in, ok := s.typeSpec(expr); ok {
+		if st, ok := spec.Type.(*ast.StructType); ok {
+			return c.structInterface(in, spec, st)
+		}
+		return c.goType(in, spec.Type)
+	}
+	return "unknown"
+}
+
+// structInterface returns the name of the interface of a named struct,
+// converting it the first time.
+func (c *tsConverter) structInterface(s source, spec *ast.TypeSpec, st *ast.StructType) string {
+	if name, ok := c.structs[spec]; ok {
+		return name
+	}
+	if c.structs == nil {
+		c.structs = map[*ast.TypeSpec]string{}
+	}
+	name := spec.Name.Name
+	for i := 2; slices.ContainsFunc(c.interfaces, func(iface Interface) bool { return iface.Name == name }) || name == "Props"; i++ {
+		name = spec.Name.Name + strconv.Itoa(i)
+	}
+	// 先登記名稱，遞迴的型別才會停止
+	c.structs[spec] = name
+	index := len(c.interfaces)
+	c.interfaces = append(c.interfaces, Interface{Name: name})
+	props := c.structProps(s, st)
+	c.interfaces[index].Props = props
+	return name
+}
+
+// valueType returns the TypeScript type of the JSON encoding of a Go value.
+func (c *tsConverter) valueType(s source, expr ast.Expr) string {
+	switch v := expr.(type) {
+	case *ast.ParenExpr:
+		return c.valueType(s, v.X)
+	case *ast.BasicLit:
+		if v.Kind == token.STRING {
+			return "string"
+		}
+		return "number"
+	case *ast.UnaryExpr:
+		if v.Op == token.NOT {
+			return "boolean"
+		}
+		return c.valueType(s, v.X)
+	case *ast.BinaryExpr:
+		switch v.Op {
+		case token.EQL, token.NEQ, token.LSS, token.LEQ, token.GTR, token.GEQ, token.LAND, token.LOR:
+			return "boolean"
+		}
+		return c.valueType(s, v.X)
+	case *ast.CompositeLit:
+		if v.Type != nil {
+			return c.goType(s, v.Type)
+		}
+	case *ast.CallExpr:
+		// 型別轉換，例如 int64(n)
+		if len(v.Args) == 1 {
+			if t := c.goType(s, v.Fun); t != "unknown" {
+				return t
+			}
+		}
+		if sel, ok := v.Fun.(*ast.SelectorExpr); ok {
+			if x, ok := sel.X.(*ast.Ident); ok && x.Obj == nil {
+				switch s.importPathOf(x.Name) + "." + sel.Sel.Name {
+				case "fmt.Sprintf", "fmt.Sprint", "strconv.Itoa", "strconv.FormatInt", "strconv.Quote", "strings.Join",
+					"strings.ToUpper", "strings.ToLower", "strings.TrimSpace", "strings.Repeat", "strings.ReplaceAll":
+					return "string"
+				}
+			}
+		}
+	case *ast.Ident:
+		switch {
+		case v.Obj == nil && (v.Name == "true" || v.Name == "false"):
+			return "boolean"
+		case v.Obj == nil && v.Name == "nil":
+			return "null"
+		case v.Obj != nil && v.Obj.Kind != ast.Con:
+			return c.declaredType(s, v)
+		}
+		if _, ok := s.stringValue(v); ok {
+			return "string"
+		}
+	case *ast.SelectorExpr:
+		if _, ok := s.stringValue(v); ok {
+			return "string"
+		}
+	}
+	return "unknown"
+}
+
+// declaredType returns the type of a local variable or constant from its declaration.
+func (c *tsConverter) declaredType(s source, id *ast.Ident) string {
+	switch decl := id.Obj.Decl.(type) {
+	case *ast.ValueSpec:
+		if decl.Type != nil {
+			return c.goType(s, decl.Type)
+		}
+		for i, name := range decl.Names {
+			if name.Name == id.Name && i < len(decl.Values) && len(decl.Values) == len(decl.Names) {
+				return c.valueType(s, decl.Values[i])
+			}
+		}
+	case *ast.AssignStmt:
+		if len(decl.Lhs) != len(decl.Rhs) {
+			return "unknown"
+		}
+		for i, lhs := range decl.Lhs {
+			if lhsID, ok := lhs.(*ast.Ident); ok && lhsID.Name == id.Name {
+				return c.valueType(s, decl.Rhs[i])
+			}
+		}
+	case *ast.Field:
+		return c.goType(s, decl.Type)
+	}
+	return "unknown"
+}
+
+// arrayType returns the array type of elements of type elem.
+func arrayType(elem string) string {
+	if strings.ContainsAny(elem, " |") {
+		return "(" + elem + ")[]"
+	}
+	return elem + "[]"
+}
+
+// inlineType returns an object type literal with props.
+func inlineType(props []Prop) string {
+	var b strings.Builder
+	b.WriteString("{ ")
+	for _, prop := range props {
+		fmt.Fprintf(&b, "%s; ", propSignature(prop))
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+// propSignature returns the TypeScript property signature of prop.
+func propSignature(prop Prop) string {
+	name := prop.Name
+	if !isIdentifier(name) {
+		name = strconv.Quote(name)
+	}
+	if prop.Optional {
+		name += "?"
+	}
+	return name + ": " + prop.Type
+}
+
+// isIdentifier reports whether name can be used as a TypeScript property name without quotes.
+func isIdentifier(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		if !(r == '_' || r == '$' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || i > 0 && r >= '0' && r <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// typeSpec returns the declaration of the named type expr refers to.
+func (s source) typeSpec(expr ast.Expr) (*ast.TypeSpec, source, bool) {
+	if spec, in, ok := s.lookup(expr, token.TYPE); ok {
+		return spec.(*ast.TypeSpec), in, true
+	}
+	return nil, source{}, false
+}
+
+// PropsFile returns the TypeScript declaration file generated for the props of
+// component, next to its Svelte file: src/pages/App.props.d.ts for pages/App.
+func PropsFile(srcDir, component string) string {
+	return strings.TrimSuffix(ComponentFile(srcDir, component), ".svelte") + ".props.d.ts"
+}
+
+// TypeScript returns a declaration file exporting the props as the interface
+// Props and the structs they refer to.
+func (pp PageProps) TypeScript() string {
+	var b strings.Builder
+	b.WriteString("// Code generated by golte-cli generate props. DO NOT EDIT.\n")
+	for _, source := range pp.Sources {
+		fmt.Fprintf(&b, "// Source: %s\n", source)
+	}
+	for _, iface := range pp.Interfaces {
+		writeInterface(&b, iface.Name, iface.Props, false)
+	}
+	writeInterface(&b, "Props", pp.Props, pp.Unknown)
+	return b.String()
+}
+
+// writeInterface writes an exported interface. open adds an index signature
+// for props that could not be determined.
+func writeInterface(b *strings.Builder, name string, props []Prop, open bool) {
+	fmt.Fprintf(b, "\nexport interface %s {\n", name)
+	for _, prop := range props {
+		fmt.Fprintf(b, "    %s;\n", propSignature(prop))
+	}
+	if open {
+		b.WriteString("    [key: string]: unknown;\n")
+	}
+	b.WriteString("}\n")
+}
@@ -0,0 +1,339 @@
+package analysis
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestPageProps(t *testing.T) {
+	const header = `package main
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/nichady/golte"
+)
+
+`
+	tests := []struct {
+		name      string
+		source    string
+		component string
+		want      string
+	}{
+		{
+			name: "map literal",
+			source: header + `const titleKey = "title"
+
+func home(w http.ResponseWriter, r *http.Request) {
+	count := 3
+	golte.RenderPage(w, r, "pages/Home", map[string]any{
+		titleKey:  "Golte",
+		"count":   count,
+		"admin":   count > 1,
+		"tags":    []string{"a"},
+		"created": time.Now(),
+		"user-id": int64(1),
+	})
+}
+`,
+			component: "pages/Home",
+			want: `// Code generated by golte-cli generate props. DO NOT EDIT.
+// Source: main.go:14
+
+export interface Props {
+    title: string;
+    count: number;
+    admin: boolean;
+    tags: string[];
+    created: unknown;
+    "user-id": number;
+}
+`,
+		},
+		{
+			name: "router map types",
+			source: `package main
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nichady/golte"
+)
+
+type M map[string]any
+
+func home(ctx *gin.Context) {
+	golte.RenderPage(ctx.Writer, ctx.Request, "pages/Home", gin.H{"title": "Golte", "meta": gin.H{}})
+}
+
+func about(ctx *gin.Context) {
+	golte.RenderPage(ctx.Writer, ctx.Request, "pages/Home", M{"title": "About"})
+}
+`,
+			component: "pages/Home",
+			want: `// Code generated by golte-cli generate props. DO NOT EDIT.
+// Source: main.go:11
+// Source: main.go:15
+
+export interface Props {
+    title: string;
+    meta?: Record<string, unknown>;
+}
+`,
+		},
+		{
+			name: "call sites are merged",
+			source: header + `func a(w http.ResponseWriter, r *http.Request) {
+	golte.RenderPage(w, r, "pages/List", map[string]any{"items": []int{1}, "page": 1})
+}
+
+func b(w http.ResponseWriter, r *http.Request) {
+	golte.RenderPage(w, r, "pages/List", map[string]any{"items": nil, "query": "x"})
+}
+`,
+			component: "pages/List",
+			want: `// Code generated by golte-cli generate props. DO NOT EDIT.
+// Source: main.go:11
+// Source: main.go:15
+
+export interface Props {
+    items: number[] | null;
+    page?: number;
+    query?: string;
+}
+`,
+		},
+		{
+			name: "struct literal",
+			source: header + `type User struct {
+	Name    string    ` + "`json:\"name\"`" + `
+	Email   *string   ` + "`json:\"email,omitempty\"`" + `
+	Friends []User    ` + "`json:\"friends\"`" + `
+	Joined  time.Time ` + "`json:\"joined\"`" + `
+	secret  string
+}
+
+type Base struct {
+	Title string
+}
+
+type ProfileProps struct {
+	Base
+	User  User              ` + "`json:\"user\"`" + `
+	Extra map[string]string ` + "`json:\"-\"`" + `
+}
+
+func profile(w http.ResponseWriter, r *http.Request) {
+	golte.RenderPage(w, r, "pages/Profile", ProfileProps{})
+}
+`,
+			component: "pages/Profile",
+			want: `// Code generated by golte-cli generate props. DO NOT EDIT.
+// Source: main.go:29
+
+export interface User {
+    name: string;
+    email?: string | null;
+    friends: User[];
+    joined: string;
+}
+
+export interface Props {
+    Title: string;
+    user: User;
+}
+`,
+		},
+		{
+			name: "annotated struct",
+			source: header + `//golte:props pages/Settings
+type SettingsProps struct {
+	Theme string ` + "`json:\"theme\"`" + `
+}
+
+func settings(w http.ResponseWriter, r *http.Request) {
+	golte.RenderPage(w, r, "pages/Settings", propsFromDB())
+}
+
+func propsFromDB() any { return nil }
+`,
+			component: "pages/Settings",
+			want: `// Code generated by golte-cli generate props. DO NOT EDIT.
+// Source: main.go:11
+
+export interface Props {
+    theme: string;
+}
+`,
+		},
+		{
+			name: "unknown props",
+			source: header + `func settings(w http.ResponseWriter, r *http.Request) {
+	golte.RenderPage(w, r, "pages/Settings", propsFromDB())
+}
+
+func propsFromDB() map[string]any { return nil }
+`,
+			component: "pages/Settings",
+			want: `// Code generated by golte-cli generate props. DO NOT EDIT.
+// Source: main.go:11
+
+export interface Props {
+    [key: string]: unknown;
+}
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			project := loadProject(t, map[string]string{"main.go": tt.source})
+			var got string
+			for _, pp := range project.PageProps() {
+				if pp.Component == tt.component {
+					got = pp.TypeScript()
+				}
+			}
+			if got != tt.want {
+				t.Errorf("TypeScript() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSvelteProps(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   []SvelteProp
+		rest   bool
+	}{
+		{
+			name: "export let",
+			source: `<script context="module">
+	export let ignored = 1;
+</script>
+
+<script lang="ts">
+	export let title: string;
+	export let count = 0;
+	export let user: { name: string } | null = null;
+</script>
+`,
+			want: []SvelteProp{
+				{Name: "title", Type: "string", Line: 6},
+				{Name: "count", HasDefault: true, Line: 7},
+				{Name: "user", Type: "{ name: string } | null", HasDefault: true, Line: 8},
+			},
+		},
+		{
+			name: "runes",
+			source: `<script lang="ts">
+	let { title, count = 0, items = [], ...others }: Props = $props();
+</script>
+`,
+			want: []SvelteProp{
+				{Name: "title", Line: 2},
+				{Name: "count", HasDefault: true, Line: 2},
+				{Name: "items", HasDefault: true, Line: 2},
+			},
+			rest: true,
+		},
+		{
+			name:   "rest props",
+			source: "<script>\n\texport let title;\n</script>\n\n<div {...$$restProps}>{title}</div>\n",
+			want:   []SvelteProp{{Name: "title", Line: 2}},
+			rest:   true,
+		},
+		{
+			name: "export let list",
+			source: `<script lang="ts">
+	export let a, b = 1;
+	export let items: Record<string, number> = {}, onClick: (e: Event) => void, label = "a, b";
+</script>
+`,
+			want: []SvelteProp{
+				{Name: "a", Line: 2},
+				{Name: "b", HasDefault: true, Line: 2},
+				{Name: "items", Type: "Record<string, number>", HasDefault: true, Line: 3},
+				{Name: "onClick", Type: "(e: Event) => void", Line: 3},
+				{Name: "label", HasDefault: true, Line: 3},
+			},
+		},
+		{
+			name:   "props object",
+			source: "<script lang=\"ts\">\n\tlet props = $props();\n</script>\n\n<h1>{props.title}</h1>\n",
+			rest:   true,
+		},
+		{
+			name:   "typed props object",
+			source: "<script lang=\"ts\">\n\tconst props: Props = $props();\n</script>\n",
+			rest:   true,
+		},
+		{
+			name:   "no script",
+			source: "<h1>Hello</h1>\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "Page.svelte")
+			if err := os.WriteFile(file, []byte(tt.source), 0644); err != nil {
+				t.Fatal(err)
+			}
+			got, rest, err := SvelteProps(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) || rest != tt.rest {
+				t.Errorf("SvelteProps() = %+v, %v, want %+v, %v", got, rest, tt.want, tt.rest)
+			}
+		})
+	}
+}
+
+func TestCheckProps(t *testing.T) {
+	const source = `package main
+
+import (
+	"net/http"
+
+	"github.com/nichady/golte"
+)
+
+func a(w http.ResponseWriter, r *http.Request) {
+	golte.RenderPage(w, r, "pages/App", map[string]any{"title": "Golte", "count": "3", "extra": 1})
+}
+
+func b(w http.ResponseWriter, r *http.Request) {
+	golte.RenderPage(w, r, "pages/App", map[string]any{"title": "Golte", "count": "4"})
+}
+`
+	const page = `<script lang="ts">
+	export let title: string;
+	export let count: number;
+	export let extra: number;
+	export let user: string;
+	export let theme = "dark";
+</script>
+`
+	project := loadProject(t, map[string]string{"main.go": source, "web/pages/App.svelte": page})
+	issues, err := project.checkProps(filepath.Join(project.Path, "web"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, issue := range issues {
+		got = append(got, issue.String())
+	}
+	want := []string{
+		`web/pages/App.svelte:3: warning: prop "count" of pages/App is declared as number, but the Go code passes a string (main.go:10)`,
+		`web/pages/App.svelte:4: warning: prop "extra" of pages/App has no default but is not always passed by the Go code`,
+		`web/pages/App.svelte:5: error: prop "user" of pages/App has no default and is not passed by the Go code`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("checkProps() =\n%q\nwant\n%q", got, want)
+	}
+}
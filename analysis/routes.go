@@ -0,0 +1,346 @@
+package analysis
+
+import (
+	"go/ast"
+	"slices"
+	"strings"
+)
+
+// Route is a route registered on a router.
+type Route struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	// Component is the page the handler renders, or "" if it renders none.
+	Component string `json:"component,omitempty"`
+	// Layouts are the layouts added by the handler, outermost first.
+	Layouts []string `json:"layouts,omitempty"`
+	// Registered is where the route is registered, Handler where its handler is defined.
+	Registered Position `json:"registered"`
+	Handler    Position `json:"handler"`
+}
+
+// routerTypes are the router types of each supported router package.
+var routerTypes = map[string][]string{
+	"github.com/gin-gonic/gin":    {"Engine", "RouterGroup", "IRouter", "IRoutes"},
+	"github.com/go-chi/chi":       {"Router", "Mux"},
+	"github.com/go-chi/chi/v5":    {"Router", "Mux"},
+	"github.com/labstack/echo/v4": {"Echo", "Group"},
+	"github.com/gofiber/fiber/v2": {"App", "Router", "Group"},
+	"net/http":                    {"ServeMux"},
+}
+
+// routerConstructors are the functions of the router packages returning a new router.
+var routerConstructors = []string{"New", "Default", "NewRouter", "NewMux", "NewServeMux"}
+
+var httpMethods = []string{"GET", "HEAD", "POST", "PUT", "PATCH", "DELETE", "CONNECT", "OPTIONS", "TRACE"}
+
+// Routes returns the routes registered in the loaded packages, in source order.
+// Routers are recognized by their type in function parameters and by the
+// constructor they are created with; gin, echo and fiber groups and chi's
+// Route and Group are followed. Routes registered on http.DefaultServeMux with
+// http.Handle and http.HandleFunc are listed as well.
+func (p *Project) Routes() []Route {
+	var routes []Route
+	for _, pkg := range p.Packages {
+		for _, file := range pkg.Syntax {
+			w := &routeWalker{source: source{project: p, pkg: pkg, file: file}, routes: &routes}
+			for _, decl := range file.Decls {
+				fn, ok := decl.(*ast.FuncDecl)
+				if !ok || fn.Body == nil {
+					continue
+				}
+				env := map[string]string{}
+				w.addRouterParams(fn.Type, env, "")
+				w.walk(fn.Body, env)
+			}
+		}
+	}
+	return routes
+}
+
+// routeWalker finds route registrations in one file. Its env maps the names of
+// router variables to their path prefix.
+type routeWalker struct {
+	source
+	routes *[]Route
+}
+
+// addRouterParams adds the router parameters of fn to env with prefix.
+func (w *routeWalker) addRouterParams(fn *ast.FuncType, env map[string]string, prefix string) {
+	for _, field := range fn.Params.List {
+		if w.isRouterType(field.Type) {
+			for _, name := range field.Names {
+				env[name.Name] = prefix
+			}
+		}
+	}
+}
+
+// isRouterType reports whether typ is a router type like *gin.Engine or chi.Router.
+func (w *routeWalker) isRouterType(typ ast.Expr) bool {
+	if star, ok := typ.(*ast.StarExpr); ok {
+		typ = star.X
+	}
+	sel, ok := typ.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return false
+	}
+	return slices.Contains(routerTypes[w.importPathOf(pkg.Name)], sel.Sel.Name)
+}
+
+// walk records the routes registered in node.
+func (w *routeWalker) walk(node ast.Node, env map[string]string) {
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			for i, rhs := range n.Rhs {
+				if id, ok := n.Lhs[min(i, len(n.Lhs)-1)].(*ast.Ident); ok {
+					if prefix, ok := w.routerPrefix(rhs, env); ok {
+						env[id.Name] = prefix
+					}
+				}
+			}
+		case *ast.ValueSpec:
+			for i, value := range n.Values {
+				if i < len(n.Names) {
+					if prefix, ok := w.routerPrefix(value, env); ok {
+						env[n.Names[i].Name] = prefix
+					}
+				}
+			}
+		case *ast.CallExpr:
+			return w.call(n, env)
+		}
+		return true
+	})
+}
+
+// routerPrefix reports whether expr evaluates to a router and returns its path prefix.
+func (w *routeWalker) routerPrefix(expr ast.Expr, env map[string]string) (string, bool) {
+	switch expr := expr.(type) {
+	case *ast.ParenExpr:
+		return w.routerPrefix(expr.X, env)
+	case *ast.Ident:
+		prefix, ok := env[expr.Name]
+		return prefix, ok
+	case *ast.CallExpr:
+		sel, ok := expr.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return "", false
+		}
+		if pkg, ok := sel.X.(*ast.Ident); ok && pkg.Obj == nil {
+			if _, isRouter := routerTypes[w.importPathOf(pkg.Name)]; isRouter && slices.Contains(routerConstructors, sel.Sel.Name) {
+				return "", true
+			}
+		}
+		prefix, ok := w.routerPrefix(sel.X, env)
+		if !ok {
+			return "", false
+		}
+		switch sel.Sel.Name {
+		case "Group":
+			// gin、echo、fiber 的 Group 以路徑為第一個參數，chi 的 Group 沒有路徑
+			if len(expr.Args) > 0 {
+				if p, ok := w.stringValue(expr.Args[0]); ok {
+					return joinPath(prefix, p), true
+				}
+			}
+			return prefix, true
+		case "With":
+			return prefix, true
+		}
+	}
+	return "", false
+}
+
+// call records the route registered by call, if any. It returns whether the
+// arguments should be inspected further.
+func (w *routeWalker) call(call *ast.CallExpr, env map[string]string) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return true
+	}
+	args := call.Args
+	name := sel.Sel.Name
+	method := strings.ToUpper(name)
+	prefix, ok := w.routerPrefix(sel.X, env)
+	if w.isDefaultMux(sel.X) {
+		// http.Handle 與 http.HandleFunc 註冊在 DefaultServeMux 上；http.Get、http.Post 等是 HTTP client
+		if name != "Handle" && name != "HandleFunc" {
+			return true
+		}
+		prefix, ok = "", true
+	}
+	if !ok {
+		return true
+	}
+
+	switch {
+	case slices.Contains(httpMethods, method) && len(args) >= 2:
+		if p, ok := w.stringValue(args[0]); ok {
+			w.add(call, method, joinPath(prefix, p), args[1:])
+			return false
+		}
+	case (name == "Any" || name == "All") && len(args) >= 2:
+		if p, ok := w.stringValue(args[0]); ok {
+			w.add(call, "ANY", joinPath(prefix, p), args[1:])
+			return false
+		}
+	case (name == "Handle" || name == "HandleFunc") && len(args) >= 2:
+		pattern, ok := w.stringValue(args[0])
+		if !ok {
+			break
+		}
+		// gin: Handle(method, path, handlers...)
+		if p, isPath := w.stringValue(args[1]); isPath && slices.Contains(httpMethods, pattern) && len(args) >= 3 {
+			w.add(call, pattern, joinPath(prefix, p), args[2:])
+			return false
+		}
+		// ServeMux: "GET /path"，沒有方法時符合所有方法
+		method := "ANY"
+		if m, p, found := strings.Cut(pattern, " "); found {
+			method, pattern = m, strings.TrimSpace(p)
+		}
+		w.add(call, method, joinPath(prefix, pattern), args[1:])
+		return false
+	case (name == "Method" || name == "MethodFunc") && len(args) >= 3:
+		// chi: Method(method, pattern, handler)
+		m, ok1 := w.stringValue(args[0])
+		p, ok2 := w.stringValue(args[1])
+		if ok1 && ok2 {
+			w.add(call, strings.ToUpper(m), joinPath(prefix, p), args[2:])
+			return false
+		}
+	case name == "Route" && len(args) == 2:
+		// chi: Route(pattern, func(r chi.Router))
+		p, ok := w.stringValue(args[0])
+		lit, isLit := args[1].(*ast.FuncLit)
+		if ok && isLit {
+			w.walkRouterFunc(lit, env, joinPath(prefix, p))
+			return false
+		}
+	case name == "Group" && len(args) == 1:
+		// chi: Group(func(r chi.Router))
+		if lit, ok := args[0].(*ast.FuncLit); ok {
+			w.walkRouterFunc(lit, env, prefix)
+			return false
+		}
+	}
+	return true
+}
+
+// isDefaultMux reports whether expr is the net/http package, whose Handle and
+// HandleFunc register routes on http.DefaultServeMux.
+func (w *routeWalker) isDefaultMux(expr ast.Expr) bool {
+	pkg, ok := expr.(*ast.Ident)
+	return ok && pkg.Obj == nil && w.importPathOf(pkg.Name) == "net/http"
+}
+
+// walkRouterFunc walks a function literal receiving a router with prefix.
+func (w *routeWalker) walkRouterFunc(lit *ast.FuncLit, env map[string]string, prefix string) {
+	inner := map[string]string{}
+	for name, p := range env {
+		inner[name] = p
+	}
+	w.addRouterParams(lit.Type, inner, prefix)
+	w.walk(lit.Body, inner)
+}
+
+// add records a route and the page and layouts its handlers render.
+func (w *routeWalker) add(call *ast.CallExpr, method, path string, handlers []ast.Expr) {
+	route := Route{
+		Method:     method,
+		Path:       path,
+		Registered: w.project.Position(call.Pos()),
+	}
+	if len(handlers) > 0 {
+		route.Handler = w.handlerPosition(handlers[len(handlers)-1])
+	}
+	seen := map[*ast.FuncDecl]bool{}
+	for _, handler := range handlers {
+		w.inspectHandler(handler, &route, seen)
+	}
+	*w.routes = append(*w.routes, route)
+}
+
+// inspectHandler finds the RenderPage, Page, AddLayout and Layout calls in a
+// handler, following the functions it refers to.
+func (w *routeWalker) inspectHandler(node ast.Node, route *Route, seen map[*ast.FuncDecl]bool) {
+	var visit func(n ast.Node) bool
+	visit = func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.CallExpr:
+			fn, arg, ok := w.componentArg(n)
+			if !ok {
+				break
+			}
+			c, ok := w.stringValue(arg)
+			if !ok {
+				break
+			}
+			switch fn {
+			case "RenderPage", "Page":
+				if route.Component == "" {
+					route.Component = c
+				}
+			case "AddLayout", "Layout":
+				route.Layouts = append(route.Layouts, c)
+			}
+		case *ast.SelectorExpr:
+			// 只有 pkg.Func 形式的選擇器可能是其他套件的函式
+			w.follow(n, route, seen)
+			ast.Inspect(n.X, visit)
+			return false
+		case *ast.Ident:
+			w.follow(n, route, seen)
+		}
+		return true
+	}
+	ast.Inspect(node, visit)
+}
+
+// follow inspects the body of the function expr refers to, once per route.
+func (w *routeWalker) follow(expr ast.Expr, route *Route, seen map[*ast.FuncDecl]bool) {
+	decl, in := w.funcDecl(expr)
+	if decl == nil || seen[decl] {
+		return
+	}
+	seen[decl] = true
+	inner := &routeWalker{source: in, routes: w.routes}
+	inner.inspectHandler(decl.Body, route, seen)
+}
+
+// handlerPosition returns where the handler expr is defined.
+func (w *routeWalker) handlerPosition(expr ast.Expr) Position {
+	switch e := expr.(type) {
+	case *ast.Ident, *ast.SelectorExpr:
+		if decl, _ := w.funcDecl(e); decl != nil {
+			return w.project.Position(decl.Pos())
+		}
+	case *ast.CallExpr:
+		// 例如 page(func(w, r) {...})，回傳包裝的函式
+		var lit *ast.FuncLit
+		ast.Inspect(e, func(n ast.Node) bool {
+			if l, ok := n.(*ast.FuncLit); ok && lit == nil {
+				lit = l
+			}
+			return lit == nil
+		})
+		if lit != nil {
+			return w.project.Position(lit.Pos())
+		}
+	}
+	return w.project.Position(expr.Pos())
+}
+
+// joinPath appends p to the group prefix.
+func joinPath(prefix, p string) string {
+	if prefix == "" {
+		return p
+	}
+	return strings.TrimSuffix(prefix, "/") + "/" + strings.TrimPrefix(p, "/")
+}
@@ -0,0 +1,235 @@
+package analysis
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestRoutes(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		// want are the routes formatted by routeString.
+		want []string
+	}{
+		{
+			name: "gin",
+			files: map[string]string{"main.go": `package main
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nichady/golte"
+)
+
+const aboutPage = "pages/About"
+
+func main() {
+	r := gin.Default()
+	r.GET("/", func(ctx *gin.Context) {
+		golte.RenderPage(ctx.Writer, ctx.Request, "pages/App", nil)
+	})
+	admin := r.Group("/admin")
+	admin.POST("/users", users)
+	r.Handle("PUT", "/about", func(ctx *gin.Context) {
+		golte.RenderPage(ctx.Writer, ctx.Request, aboutPage, nil)
+	})
+	r.Any("/healthz", func(ctx *gin.Context) {})
+}
+
+func users(ctx *gin.Context) {
+	golte.AddLayout(ctx.Request, "layouts/Admin", nil)
+	golte.RenderPage(ctx.Writer, ctx.Request, "pages/admin/Users", nil)
+}
+`},
+			want: []string{
+				"GET / pages/App [] main.go:12 main.go:12",
+				"POST /admin/users pages/admin/Users [layouts/Admin] main.go:16 main.go:23",
+				"PUT /about pages/About [] main.go:17 main.go:17",
+				"ANY /healthz  [] main.go:20 main.go:20",
+			},
+		},
+		{
+			name: "chi",
+			files: map[string]string{
+				"router/router.go": `package router
+
+import (
+	"net/http"
+
+	"example.com/app/handlers"
+	"github.com/HazelnutParadise/sveltigo"
+	"github.com/go-chi/chi/v5"
+)
+
+func defineRoutes(r chi.Router) {
+	r.Get("/", handlers.Home)
+	r.Route("/admin", func(r chi.Router) {
+		r.Use(auth)
+		r.Post("/users", handlers.Users)
+	})
+	r.Group(func(r chi.Router) {
+		r.Method("delete", "/items", http.HandlerFunc(handlers.Users))
+	})
+	r.With(auth).Put("/profile", handlers.Home)
+	r.Get("/about", sveltigo.Page("pages/About"))
+}
+
+func auth(next http.Handler) http.Handler { return next }
+`,
+				"handlers/handlers.go": `package handlers
+
+import (
+	"net/http"
+
+	"github.com/HazelnutParadise/sveltigo"
+)
+
+func Home(w http.ResponseWriter, r *http.Request) {
+	sveltigo.RenderPage(w, r, "pages/Home", nil)
+}
+
+func Users(w http.ResponseWriter, r *http.Request) {
+	sveltigo.AddLayout(r, "layouts/Main", nil)
+	sveltigo.RenderPage(w, r, "pages/Users", nil)
+}
+`,
+			},
+			want: []string{
+				"GET / pages/Home [] router/router.go:12 handlers/handlers.go:9",
+				"POST /admin/users pages/Users [layouts/Main] router/router.go:15 handlers/handlers.go:13",
+				"DELETE /items pages/Users [layouts/Main] router/router.go:18 router/router.go:18",
+				"PUT /profile pages/Home [] router/router.go:20 handlers/handlers.go:9",
+				"GET /about pages/About [] router/router.go:21 router/router.go:21",
+			},
+		},
+		{
+			name: "echo",
+			files: map[string]string{"main.go": `package main
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/nichady/golte"
+)
+
+func main() {
+	e := echo.New()
+	api := e.Group("/api")
+	v1 := api.Group("/v1")
+	v1.GET("/status", func(c echo.Context) error { return nil })
+	e.POST("/contact", func(c echo.Context) error {
+		golte.RenderPage(c.Response(), c.Request(), "pages/Contact", nil)
+		return nil
+	})
+}
+`},
+			want: []string{
+				"GET /api/v1/status  [] main.go:12 main.go:12",
+				"POST /contact pages/Contact [] main.go:13 main.go:13",
+			},
+		},
+		{
+			name: "fiber",
+			files: map[string]string{"router.go": `package router
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/nichady/golte"
+)
+
+func defineRoutes(app *fiber.App) {
+	app.Get("/", page(func(w http.ResponseWriter, r *http.Request) {
+		golte.RenderPage(w, r, "pages/App", nil)
+	}))
+	api := app.Group("/api/")
+	api.All("/echo", func(c *fiber.Ctx) error { return nil })
+}
+
+func page(h http.HandlerFunc) fiber.Handler { return nil }
+`},
+			want: []string{
+				"GET / pages/App [] router.go:11 router.go:11",
+				"ANY /api/echo  [] router.go:15 router.go:15",
+			},
+		},
+		{
+			name: "ServeMux",
+			files: map[string]string{"main.go": `package main
+
+import (
+	"net/http"
+
+	"github.com/nichady/golte"
+)
+
+const prefix = "/app"
+
+func main() {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		golte.RenderPage(w, r, "pages/App", nil)
+	})
+	mux.Handle("/static/", http.FileServer(http.Dir("static")))
+	mux.HandleFunc("POST "+prefix+"/save", save)
+	registerMore(mux)
+}
+
+func registerMore(m *http.ServeMux) {
+	m.HandleFunc("DELETE /items/{id}", save)
+}
+
+func save(w http.ResponseWriter, r *http.Request) {}
+`},
+			want: []string{
+				"GET /{$} pages/App [] main.go:13 main.go:13",
+				"ANY /static/  [] main.go:16 main.go:16",
+				"POST /app/save  [] main.go:17 main.go:25",
+				"DELETE /items/{id}  [] main.go:22 main.go:25",
+			},
+		},
+		{
+			name: "DefaultServeMux",
+			files: map[string]string{"main.go": `package main
+
+import (
+	"net/http"
+
+	"github.com/nichady/golte"
+)
+
+func main() {
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		golte.AddLayout(r, "layouts/Main", nil)
+		golte.RenderPage(w, r, "pages/App", nil)
+	})
+	http.Handle("GET /static/", http.FileServer(http.Dir("static")))
+	resp, _ := http.Post("https://example.com", "text/plain", nil)
+	_ = resp
+	http.ListenAndServe(":8000", nil)
+}
+`},
+			want: []string{
+				"ANY / pages/App [layouts/Main] main.go:10 main.go:10",
+				"GET /static/  [] main.go:14 main.go:14",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, route := range loadProject(t, tt.files).Routes() {
+				got = append(got, routeString(route))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Routes() =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+// routeString formats r as "METHOD path component [layouts] registered handler".
+func routeString(r Route) string {
+	return fmt.Sprintf("%s %s %s %v %s %s", r.Method, r.Path, r.Component, r.Layouts, r.Registered, r.Handler)
+}
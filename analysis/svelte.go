@@ -0,0 +1,156 @@
+package analysis
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// SvelteProp is a prop declared by a Svelte component.
+type SvelteProp struct {
+	Name string
+	// Type is the TypeScript type annotation, or "" if there is none.
+	Type       string
+	HasDefault bool
+	Line       int
+}
+
+var (
+	scriptPattern    = regexp.MustCompile(`(?s)<script([^>]*)>(.*?)</script>`)
+	exportLetPattern = regexp.MustCompile(`(?m)\bexport\s+let\s+([^;\n]+)`)
+	identPattern     = regexp.MustCompile(`^[A-Za-z_$][\w$]*`)
+	runesPattern     = regexp.MustCompile(`(?s)\blet\s*\{(.*?)\}\s*(?::\s*[^=]+?)?=\s*\$props\(\)`)
+	modulePattern    = regexp.MustCompile(`\bmodule\b`)
+
+	// allPropsPattern 是沒有解構的 let props = $props()，元件接受任何 prop
+	allPropsPattern = regexp.MustCompile(`\b(?:let|const)\s+[A-Za-z_$][\w$]*\s*(?::\s*[^=]+?)?=\s*\$props\(\)`)
+)
+
+// SvelteProps returns the props the instance script of a Svelte component
+// declares with `export let` or, in Svelte 5, `let { ... } = $props()`. rest
+// reports whether the component accepts any prop, through $$props,
+// $$restProps or a rest element.
+func SvelteProps(file string) (props []SvelteProp, rest bool, err error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read %s: %v", file, err)
+	}
+	text := string(content)
+	rest = strings.Contains(text, "$$props") || strings.Contains(text, "$$restProps")
+	lineAt := func(offset int) int {
+		return strings.Count(text[:offset], "\n") + 1
+	}
+
+	for _, script := range scriptPattern.FindAllStringSubmatchIndex(text, -1) {
+		attrs := text[script[2]:script[3]]
+		// <script context="module"> 與 Svelte 5 的 <script module> 不宣告 props
+		if modulePattern.MatchString(attrs) {
+			continue
+		}
+		start, body := script[4], text[script[4]:script[5]]
+
+		for _, m := range exportLetPattern.FindAllStringSubmatchIndex(body, -1) {
+			// export let a, b: string = "", c; 宣告多個 props
+			line := lineAt(start + m[0])
+			for _, declarator := range splitTopLevel(body[m[2]:m[3]]) {
+				if prop, ok := exportedProp(declarator); ok {
+					prop.Line = line
+					props = append(props, prop)
+				}
+			}
+		}
+		if allPropsPattern.MatchString(body) {
+			rest = true
+		}
+
+		for _, m := range runesPattern.FindAllStringSubmatchIndex(body, -1) {
+			line := lineAt(start + m[0])
+			for _, element := range splitTopLevel(body[m[2]:m[3]]) {
+				if strings.HasPrefix(element, "...") {
+					rest = true
+					continue
+				}
+				name, _, hasDefault := strings.Cut(element, "=")
+				// { title: heading = "" } 的 prop 名稱是冒號前的部分
+				name, _, _ = strings.Cut(name, ":")
+				if name = strings.TrimSpace(name); name != "" {
+					props = append(props, SvelteProp{Name: name, HasDefault: hasDefault, Line: line})
+				}
+			}
+		}
+	}
+	return props, rest, nil
+}
+
+// exportedProp parses a declarator of `export let`: a name with an optional
+// type annotation and default value.
+func exportedProp(declarator string) (SvelteProp, bool) {
+	name := identPattern.FindString(declarator)
+	if name == "" {
+		return SvelteProp{}, false
+	}
+	prop := SvelteProp{Name: name}
+	rest := strings.TrimSpace(declarator[len(name):])
+	typ, hasDefault := rest, false
+	if i := topLevelAssign(rest); i >= 0 {
+		typ, hasDefault = rest[:i], true
+	}
+	if typ, ok := strings.CutPrefix(strings.TrimSpace(typ), ":"); ok {
+		prop.Type = strings.TrimSpace(typ)
+	}
+	prop.HasDefault = hasDefault
+	return prop, true
+}
+
+// topLevelAssign returns the offset of the = that starts the default value in
+// a declarator, skipping => and the ones nested in brackets, or -1.
+func topLevelAssign(s string) int {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '(' || c == '[' || c == '{' || c == '<':
+			depth++
+		case c == ')' || c == ']' || c == '}' || c == '>' && depth > 0 && s[i-1] != '=':
+			depth--
+		case c == '=' && depth == 0 && (i+1 == len(s) || s[i+1] != '>'):
+			return i
+		}
+	}
+	return -1
+}
+
+// splitTopLevel splits a destructuring pattern or declarator list at the
+// commas that are not nested in brackets, braces, parentheses, type arguments
+// or strings.
+func splitTopLevel(s string) []string {
+	var parts []string
+	depth, angles, start := 0, 0, 0
+	var quote rune
+	for i, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote && (i == 0 || s[i-1] != '\\') {
+				quote = 0
+			}
+		case r == '"' || r == '\'' || r == '`':
+			quote = r
+		case r == '(' || r == '[' || r == '{':
+			depth++
+		case r == ')' || r == ']' || r == '}':
+			depth--
+		case r == '<':
+			angles++
+		case r == '>' && angles > 0 && s[i-1] != '=':
+			// Record<string, number> 的逗號不分隔宣告，=> 不是型別參數的結尾
+			angles--
+		case r == ',' && depth == 0 && angles == 0:
+			parts = append(parts, strings.TrimSpace(s[start:i]))
+			start = i + 1
+		}
+	}
+	if last := strings.TrimSpace(s[start:]); last != "" {
+		parts = append(parts, last)
+	}
+	return parts
+}
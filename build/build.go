@@ -6,45 +6,125 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strings"
+
+	"github.com/TimLai666/golte-cli/config"
+	"github.com/TimLai666/golte-cli/install"
 )
 
-func BuildProject(projectPath string, projectName string, isSveltigo bool, bunPath string) bool {
-	log.Println("Starting frontend build...")
-	// build frontend
-	cmd := exec.Command(bunPath, "x", "golte")
-	cmd.Dir = projectPath
-	if output, err := cmd.CombinedOutput(); err != nil {
-		log.Printf("Failed to build frontend: %v\n%s", err, output)
-		return false
+// Options configures BuildProject.
+type Options struct {
+	ProjectPath string
+	ProjectName string
+	IsSveltigo  bool
+	BunPath     string
+	// BunVersion is the Bun version the project pins, if any.
+	BunVersion string
+	// Entry is the file or package passed to `go build`. It defaults to main.go.
+	Entry string
+	// Output is where the binary is written, relative to ProjectPath.
+	// It defaults to dist/<ProjectName>.
+	Output string
+	// Flags, LDFlags and Tags are passed to `go build`.
+	Flags   []string
+	LDFlags string
+	Tags    []string
+	// SkipFrontend reuses the existing frontend build output.
+	SkipFrontend bool
+	// SkipModTidy skips `go mod tidy`.
+	SkipModTidy bool
+	// Check runs CheckComponents before the build. Its errors fail the build;
+	// its warnings are only printed unless Strict is set. See CheckFailed.
+	Check  bool
+	Strict bool
+}
+
+// ExecutablePath returns output with the platform's executable suffix.
+func ExecutablePath(output string) string {
+	if runtime.GOOS == "windows" && filepath.Ext(output) != ".exe" {
+		return fmt.Sprintf("%s.exe", output)
 	}
-	if isSveltigo {
-		changeSveltigoMiddlewareFile(projectPath)
+	return output
+}
+
+// StagingPath returns where `dev` builds a new binary for output before
+// swapping it in.
+func StagingPath(output string) string {
+	return filepath.Join(filepath.Dir(output), ".staging", filepath.Base(ExecutablePath(output)))
+}
+
+// BuildProject builds the frontend and the Go binary.
+// On failure it returns a *BuildError describing the failed stage.
+func BuildProject(opts Options) error {
+	projectPath := opts.ProjectPath
+	output := opts.Output
+	if output == "" {
+		output = filepath.Join("dist", opts.ProjectName)
+	}
+	output = ExecutablePath(output)
+	entry := opts.Entry
+	if entry == "" {
+		entry = "main.go"
+	}
+
+	// make sure the pinned bun version is used
+	if err := install.CheckBunVersion(opts.BunPath, opts.BunVersion); err != nil {
+		return newBuildError(StageToolchain, nil, err)
+	}
+
+	if opts.Check {
+		if err := checkBeforeBuild(projectPath, opts.BunPath, opts.Strict); err != nil {
+			return newBuildError(StageCheck, nil, err)
+		}
+	}
+
+	if opts.SkipFrontend {
+		log.Println("Skipping frontend build")
+	} else {
+		log.Println("Starting frontend build...")
+		// build frontend
+		cmd := exec.Command(opts.BunPath, "x", "golte")
+		cmd.Dir = projectPath
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return newBuildError(StageFrontend, output, err)
+		}
+		if opts.IsSveltigo {
+			golteConfig, err := config.LoadGolteConfig(projectPath, opts.BunPath)
+			if err != nil {
+				return newBuildError(StageSveltigoPatch, nil, err)
+			}
+			if err := changeSveltigoMiddlewareFile(golteConfig.OutPath()); err != nil {
+				return newBuildError(StageSveltigoPatch, nil, err)
+			}
+		}
+		log.Println("Frontend build completed")
 	}
-	log.Println("Frontend build completed")
 
 	log.Println("Starting backend build...")
 	// tidy go mod
-	cmd = exec.Command("go", "mod", "tidy")
-	cmd.Dir = projectPath
-	if output, err := cmd.CombinedOutput(); err != nil {
-		log.Printf("Failed to tidy go mod: %v\n%s", err, output)
-		return false
+	if !opts.SkipModTidy {
+		cmd := exec.Command("go", "mod", "tidy")
+		cmd.Dir = projectPath
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return newBuildError(StageModTidy, output, err)
+		}
 	}
 
 	// build the project
-	var execName string
-	if runtime.GOOS == "windows" {
-		execName = fmt.Sprintf("%s.exe", projectName)
-	} else {
-		execName = projectName
+	args := append([]string{"build"}, opts.Flags...)
+	if opts.LDFlags != "" {
+		args = append(args, "-ldflags", opts.LDFlags)
+	}
+	if len(opts.Tags) > 0 {
+		args = append(args, "-tags", strings.Join(opts.Tags, ","))
 	}
-	cmd = exec.Command("go", "build", "-o", filepath.Join("dist", execName), "main.go")
+	args = append(args, "-o", output, entry)
+	cmd := exec.Command("go", args...)
 	cmd.Dir = projectPath
 	if output, err := cmd.CombinedOutput(); err != nil {
-		log.Printf("Failed to build project: %v\n%s", err, output)
-		return false
+		return newBuildError(StageGoBuild, output, err)
 	}
 	log.Println("Backend build completed")
 
-	return true
+	return nil
 }
@@ -1,10 +1,9 @@
 package build
 
 import (
-	"log"
+	"fmt"
 	"os"
 	"path/filepath"
-	"regexp"
 )
 
 const sveltigoMiddlewareFile = `package build
@@ -22,21 +21,17 @@ var fsys embed.FS
 var Sveltigo = sveltigo.New(&fsys)
 `
 
-func changeSveltigoMiddlewareFile(projectPath string) {
-	// 從 golte.config.ts 中讀取 buildPath，用字串讀
-	configFile, err := os.ReadFile(filepath.Join(projectPath, "golte.config.ts"))
-	if err != nil {
-		log.Fatalf("Failed to read golte.config.ts file: %v", err)
-	}
-	// 用正則表達式找到 outDir
-	re := regexp.MustCompile(`outDir: "(.+)"`)
-	buildPath := re.FindStringSubmatch(string(configFile))[1]
+// changeSveltigoMiddlewareFile 將 golte 產生的 embed.go 換成 sveltigo 的版本
+func changeSveltigoMiddlewareFile(outPath string) error {
 	// 尋找 embed.go 文件
-	embedFilePath := filepath.Join(buildPath, "embed.go")
+	embedFilePath := filepath.Join(outPath, "embed.go")
 	if _, err := os.Stat(embedFilePath); os.IsNotExist(err) {
-		log.Fatalf("Embed file not found: %v", err)
+		return fmt.Errorf("embed file not found: %v", err)
 	}
 	// 替代為 sveltigoMiddlewareFile 的內容
 	content := sveltigoMiddlewareFile
-	os.WriteFile(embedFilePath, []byte(content), 0644)
+	if err := os.WriteFile(embedFilePath, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %v", embedFilePath, err)
+	}
+	return nil
 }
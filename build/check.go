@@ -0,0 +1,57 @@
+package build
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/TimLai666/golte-cli/analysis"
+	"github.com/TimLai666/golte-cli/config"
+)
+
+// CheckComponents statically checks that the components passed to RenderPage,
+// AddLayout and the other Golte and Sveltigo functions exist under the srcDir
+// of golte.config.ts and that every page is rendered.
+func CheckComponents(projectPath, bunPath string) ([]analysis.Issue, error) {
+	golteConfig, err := config.LoadGolteConfig(projectPath, bunPath)
+	if err != nil {
+		return nil, err
+	}
+	project, err := analysis.Load(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	return project.Check(golteConfig.SrcPath())
+}
+
+// CheckFailed returns an error if the issues fail the check: any error, such
+// as a missing component that would only fail at runtime, or with strict any
+// warning as well. `check` and `build` share this rule.
+func CheckFailed(issues []analysis.Issue, strict bool) error {
+	errorCount := 0
+	for _, issue := range issues {
+		if issue.Severity == analysis.SeverityError {
+			errorCount++
+		}
+	}
+	if errorCount > 0 {
+		return fmt.Errorf("%d error(s) found", errorCount)
+	}
+	if strict && len(issues) > 0 {
+		return fmt.Errorf("%d warning(s) found in strict mode", len(issues))
+	}
+	return nil
+}
+
+// checkBeforeBuild 印出檢查結果，有錯誤或 strict 模式下有警告時失敗
+func checkBeforeBuild(projectPath, bunPath string, strict bool) error {
+	log.Println("Checking components...")
+	issues, err := CheckComponents(projectPath, bunPath)
+	if err != nil {
+		return err
+	}
+	for _, issue := range issues {
+		fmt.Fprintln(os.Stderr, issue)
+	}
+	return CheckFailed(issues, strict)
+}
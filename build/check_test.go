@@ -0,0 +1,31 @@
+package build
+
+import (
+	"testing"
+
+	"github.com/TimLai666/golte-cli/analysis"
+)
+
+func TestCheckFailed(t *testing.T) {
+	problem := analysis.Issue{Severity: analysis.SeverityError, Message: "missing"}
+	warning := analysis.Issue{Severity: analysis.SeverityWarning, Message: "unused"}
+	tests := []struct {
+		name   string
+		issues []analysis.Issue
+		strict bool
+		fail   bool
+	}{
+		{name: "no issues", strict: true},
+		{name: "warning", issues: []analysis.Issue{warning}},
+		{name: "warning in strict mode", issues: []analysis.Issue{warning}, strict: true, fail: true},
+		{name: "error", issues: []analysis.Issue{problem, warning}, fail: true},
+		{name: "error in strict mode", issues: []analysis.Issue{problem}, strict: true, fail: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := CheckFailed(tt.issues, tt.strict); (err != nil) != tt.fail {
+				t.Errorf("CheckFailed() = %v, want failure %v", err, tt.fail)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package build
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+)
+
+// Stage identifies the step of the build pipeline that failed.
+type Stage string
+
+const (
+	StageToolchain     Stage = "toolchain"
+	StageCheck         Stage = "check"
+	StageFrontend      Stage = "frontend"
+	StageSveltigoPatch Stage = "sveltigo-patch"
+	StageModTidy       Stage = "mod-tidy"
+	StageGoBuild       Stage = "go-build"
+)
+
+// BuildError is returned by BuildProject when one of the build stages fails.
+type BuildError struct {
+	Stage    Stage
+	Output   []byte // combined stdout/stderr of the failed command, if any
+	ExitCode int    // exit code of the failed command, -1 if it did not exit normally
+	Err      error
+}
+
+func (e *BuildError) Error() string {
+	if e.ExitCode > 0 {
+		return fmt.Sprintf("%s stage failed (exit code %d): %v", e.Stage, e.ExitCode, e.Err)
+	}
+	return fmt.Sprintf("%s stage failed: %v", e.Stage, e.Err)
+}
+
+func (e *BuildError) Unwrap() error {
+	return e.Err
+}
+
+func newBuildError(stage Stage, output []byte, err error) *BuildError {
+	exitCode := -1
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		exitCode = exitErr.ExitCode()
+	}
+	return &BuildError{
+		Stage:    stage,
+		Output:   output,
+		ExitCode: exitCode,
+		Err:      err,
+	}
+}
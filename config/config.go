@@ -0,0 +1,215 @@
+package config
+
+import (
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+
+	"github.com/TimLai666/golte-cli/install"
+)
+
+// FileName is the name of the project configuration file.
+const FileName = "golte-cli.toml"
+
+const (
+	FlavorGolte    = "golte"
+	FlavorSveltigo = "sveltigo"
+)
+
+// Config is the resolved project configuration. Values are applied in the order
+// defaults < detected from the project < golte-cli.toml < environment variables
+// < command line flags.
+type Config struct {
+	// Flavor is "golte" or "sveltigo".
+	Flavor string `toml:"flavor"`
+	// Name is the binary name. It defaults to the last element of the module path.
+	Name string `toml:"name"`
+	// Entry is the file or package passed to `go build`.
+	Entry string `toml:"entry"`
+	// Output is the path of the built binary, relative to the project.
+	Output string `toml:"output"`
+	// Env is added to the environment of the app started by `run` and `dev`.
+	Env map[string]string `toml:"env,omitempty"`
+
+	BunPath        string   `toml:"bunPath,omitempty"`
+	BunSearchPaths []string `toml:"bunSearchPaths,omitempty"`
+	BunVersion     string   `toml:"bunVersion,omitempty"`
+	BunMirror      string   `toml:"bunMirror,omitempty"`
+	// BunSHA256 pins the SHA-256 of the bunVersion release archives by asset,
+	// like "bun-linux-x64". Other assets are verified against the official release.
+	BunSHA256 map[string]string `toml:"bunSha256,omitempty"`
+
+	Build BuildConfig `toml:"build"`
+	Watch WatchConfig `toml:"watch"`
+
+	// DetectedFlavor is the flavor the project's code actually uses, or "" if unknown.
+	DetectedFlavor string `toml:"-"`
+
+	// ProjectPath is the directory the configuration was loaded for.
+	ProjectPath string `toml:"-"`
+	// File is the configuration file that was read, if any.
+	File string `toml:"-"`
+}
+
+// BuildConfig holds extra arguments for `go build` and how `build` checks the project.
+type BuildConfig struct {
+	Flags   []string `toml:"flags"`
+	LDFlags string   `toml:"ldflags"`
+	Tags    []string `toml:"tags"`
+	// Strict fails `build` when `check` finds a problem.
+	Strict bool `toml:"strict"`
+}
+
+// WatchConfig controls which changes `dev` rebuilds on.
+type WatchConfig struct {
+	// Extensions are the file extensions that trigger a rebuild.
+	Extensions []string `toml:"extensions"`
+	// Exclude are directory names that are not watched, in addition to
+	// DefaultWatchExclude.
+	Exclude []string `toml:"exclude"`
+}
+
+// DefaultWatchExtensions are the extensions watched when the config sets none.
+var DefaultWatchExtensions = []string{".go", ".svelte", ".css", ".html", ".ts", ".js", ".json", ".mod", ".sum"}
+
+// DefaultWatchExclude are the directories never watched by `dev`. The outDir
+// of golte.config.ts is excluded as well.
+var DefaultWatchExclude = []string{"node_modules", "dist", ".git"}
+
+// Load reads golte-cli.toml from projectPath if it exists, then applies the
+// GOLTE_* environment variables and defaults.
+func Load(projectPath string) (*Config, error) {
+	cfg := &Config{ProjectPath: projectPath}
+
+	file := filepath.Join(projectPath, FileName)
+	if _, err := os.Stat(file); err == nil {
+		if _, err := toml.DecodeFile(file, cfg); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %v", file, err)
+		}
+		cfg.File = file
+	}
+
+	cfg.applyEnv()
+	if err := cfg.applyDefaults(); err != nil {
+		return nil, err
+	}
+	return cfg, cfg.Validate()
+}
+
+// FromEnv returns the settings of the GOLTE_* environment variables without
+// reading a project, for commands like `new` that do not run in one.
+func FromEnv() *Config {
+	cfg := &Config{}
+	cfg.applyEnv()
+	return cfg
+}
+
+// applyEnv 讀取環境變數，覆蓋設定檔的值
+func (c *Config) applyEnv() {
+	setFromEnv(&c.Flavor, "GOLTE_FLAVOR")
+	setFromEnv(&c.Name, "GOLTE_NAME")
+	setFromEnv(&c.Entry, "GOLTE_ENTRY")
+	setFromEnv(&c.Output, "GOLTE_OUTPUT")
+	setFromEnv(&c.BunPath, install.BunEnvVar)
+	setFromEnv(&c.BunVersion, "GOLTE_BUN_VERSION")
+	setFromEnv(&c.BunMirror, install.MirrorEnvVar)
+	if flags := os.Getenv("GOLTE_BUILD_FLAGS"); flags != "" {
+		c.Build.Flags = strings.Fields(flags)
+	}
+	setFromEnv(&c.Build.LDFlags, "GOLTE_LDFLAGS")
+}
+
+func setFromEnv(value *string, name string) {
+	if v := os.Getenv(name); v != "" {
+		*value = v
+	}
+}
+
+func (c *Config) applyDefaults() error {
+	c.DetectedFlavor = DetectFlavor(c.ProjectPath)
+	if c.Flavor == "" {
+		c.Flavor = c.DetectedFlavor
+	}
+	if c.Flavor == "" {
+		c.Flavor = FlavorGolte
+	}
+	if c.Name == "" {
+		c.Name = binaryName(ModulePath(c.ProjectPath))
+	}
+	if c.Name == "" {
+		c.Name = filepath.Base(c.ProjectPath)
+	}
+	if c.Entry == "" {
+		c.Entry = "main.go"
+	}
+	if c.Output == "" {
+		c.Output = filepath.Join("dist", c.Name)
+	}
+	if len(c.Watch.Extensions) == 0 {
+		c.Watch.Extensions = DefaultWatchExtensions
+	}
+	for _, dir := range DefaultWatchExclude {
+		if !slices.Contains(c.Watch.Exclude, dir) {
+			c.Watch.Exclude = append(c.Watch.Exclude, dir)
+		}
+	}
+	if c.BunVersion == "" {
+		version, err := install.ProjectBunVersion(c.ProjectPath)
+		if err != nil {
+			return err
+		}
+		c.BunVersion = version
+	}
+	return nil
+}
+
+// Validate reports invalid configuration values.
+func (c *Config) Validate() error {
+	if c.Flavor != FlavorGolte && c.Flavor != FlavorSveltigo {
+		return fmt.Errorf("invalid flavor %q: must be %q or %q", c.Flavor, FlavorGolte, FlavorSveltigo)
+	}
+	for asset, sum := range c.BunSHA256 {
+		if _, err := hex.DecodeString(sum); err != nil || len(sum) != 64 {
+			return fmt.Errorf("invalid bunSha256 for %s: must be 64 hexadecimal digits", asset)
+		}
+	}
+	return nil
+}
+
+// FlavorMismatch returns a warning if the configured flavor differs from the one
+// the project's code uses, or "" if they agree.
+func (c *Config) FlavorMismatch() string {
+	if c.DetectedFlavor == "" || c.Flavor == c.DetectedFlavor {
+		return ""
+	}
+	return fmt.Sprintf("flavor is set to %q but the project uses %s; the build may break at runtime. "+
+		"Remove the --sveltigo flag or the flavor setting to use the detected flavor", c.Flavor, c.DetectedFlavor)
+}
+
+// IsSveltigo reports whether the project uses Sveltigo instead of Golte.
+func (c *Config) IsSveltigo() bool {
+	return c.Flavor == FlavorSveltigo
+}
+
+// Environ returns the environment for the app: the current environment plus Env.
+func (c *Config) Environ() []string {
+	env := os.Environ()
+	for key, value := range c.Env {
+		env = append(env, key+"="+value)
+	}
+	return env
+}
+
+// String returns the configuration in golte-cli.toml format.
+func (c *Config) String() string {
+	var b strings.Builder
+	if err := toml.NewEncoder(&b).Encode(c); err != nil {
+		return fmt.Sprintf("failed to encode config: %v", err)
+	}
+	return b.String()
+}
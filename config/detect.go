@@ -0,0 +1,87 @@
+package config
+
+import (
+	"bufio"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	golteModule    = "github.com/nichady/golte"
+	sveltigoModule = "github.com/HazelnutParadise/sveltigo"
+)
+
+// DetectFlavor guesses the project flavor from the imports in router/*.go and,
+// failing that, from the direct requirements in go.mod. It returns "" if the
+// project uses neither Golte nor Sveltigo.
+func DetectFlavor(projectPath string) string {
+	if flavor := flavorFromImports(filepath.Join(projectPath, "router")); flavor != "" {
+		return flavor
+	}
+	return flavorFromGoMod(filepath.Join(projectPath, "go.mod"))
+}
+
+// flavorFromImports 檢查目錄中的 Go 檔案匯入了哪個套件
+func flavorFromImports(dir string) string {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return ""
+	}
+	var usesGolte bool
+	for _, path := range files {
+		file, err := parser.ParseFile(token.NewFileSet(), path, nil, parser.ImportsOnly)
+		if err != nil {
+			continue
+		}
+		for _, spec := range file.Imports {
+			importPath, _ := strconv.Unquote(spec.Path.Value)
+			switch {
+			case importPath == sveltigoModule || strings.HasPrefix(importPath, sveltigoModule+"/"):
+				return FlavorSveltigo
+			case importPath == golteModule || strings.HasPrefix(importPath, golteModule+"/"):
+				usesGolte = true
+			}
+		}
+	}
+	if usesGolte {
+		return FlavorGolte
+	}
+	return ""
+}
+
+// flavorFromGoMod 檢查 go.mod 中直接依賴的套件（忽略 // indirect）
+func flavorFromGoMod(path string) string {
+	file, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	var usesGolte bool
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.Contains(line, "// indirect") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "require ")
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		switch fields[0] {
+		case sveltigoModule:
+			return FlavorSveltigo
+		case golteModule:
+			usesGolte = true
+		}
+	}
+	if usesGolte {
+		return FlavorGolte
+	}
+	return ""
+}
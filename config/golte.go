@@ -0,0 +1,319 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// GolteConfigFile is the name of the Golte configuration file.
+const GolteConfigFile = "golte.config.ts"
+
+// GolteConfig holds the options of golte.config.ts. Paths are relative to the project.
+type GolteConfig struct {
+	Template  string `json:"template"`
+	SrcDir    string `json:"srcDir"`
+	OutDir    string `json:"outDir"`
+	AssetsDir string `json:"assetsDir"`
+	AppPath   string `json:"appPath"`
+	// Raw holds every key of the config, including the ones above.
+	Raw map[string]any `json:"-"`
+
+	// ProjectPath is the directory golte.config.ts was loaded from.
+	ProjectPath string `json:"-"`
+}
+
+// defaultGolteConfig 與 golte 的預設值相同
+func defaultGolteConfig(projectPath string) *GolteConfig {
+	return &GolteConfig{
+		Template:    "web/app.html",
+		SrcDir:      "web/",
+		OutDir:      "build/",
+		Raw:         map[string]any{},
+		ProjectPath: projectPath,
+	}
+}
+
+// LoadGolteConfig reads golte.config.ts from projectPath. If bunPath is set the module
+// is evaluated with Bun, so any valid TypeScript works; otherwise, or if the evaluation
+// fails, the literal options of the default export are read with a tolerant parser
+// and a warning is logged for the evaluation error and the options it cannot read.
+// Missing options keep Golte's defaults.
+func LoadGolteConfig(projectPath, bunPath string) (*GolteConfig, error) {
+	cfg := defaultGolteConfig(projectPath)
+	path := filepath.Join(projectPath, GolteConfigFile)
+	source, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", GolteConfigFile, err)
+	}
+
+	raw, err := evalGolteConfig(path, bunPath)
+	if err != nil {
+		if bunPath != "" {
+			log.Printf("Warning: %v\nFalling back to reading the literal options of %s", err, GolteConfigFile)
+		}
+		var unread []string
+		raw, unread = parseGolteConfig(string(source))
+		if len(unread) > 0 {
+			log.Printf("Warning: could not read %s in %s because the values are not literals, Golte's defaults are used instead",
+				strings.Join(unread, ", "), GolteConfigFile)
+		}
+	}
+
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", GolteConfigFile, err)
+	}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("invalid option in %s: %v", GolteConfigFile, err)
+	}
+	cfg.Raw = raw
+	return cfg, nil
+}
+
+// evalScript 以 Bun 載入設定模組並輸出 default export 的 JSON
+const evalScript = `const mod = await import(process.env.GOLTE_CONFIG_PATH);
+console.log(JSON.stringify(mod.default ?? {}));`
+
+func evalGolteConfig(path, bunPath string) (map[string]any, error) {
+	if bunPath == "" {
+		return nil, fmt.Errorf("bun is not available")
+	}
+	cmd := exec.Command(bunPath, "-e", evalScript)
+	cmd.Dir = filepath.Dir(path)
+	cmd.Env = append(os.Environ(), "GOLTE_CONFIG_PATH="+path)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("failed to evaluate %s: %v\n%s", GolteConfigFile, err, msg)
+		}
+		return nil, fmt.Errorf("failed to evaluate %s: %v", GolteConfigFile, err)
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(bytes.TrimSpace(output), &raw); err != nil {
+		return nil, fmt.Errorf("failed to evaluate %s: %v", GolteConfigFile, err)
+	}
+	return raw, nil
+}
+
+// jsToken is a token of golte.config.ts as seen by the tolerant parser.
+type jsToken struct {
+	kind byte // 's' string, 't' template literal with ${}, 'u' unterminated string, 'w' word or number, 'p' punctuation
+	text string
+}
+
+// tokenizeConfig splits source into tokens, skipping whitespace and comments.
+// Strings are kept whole, so // and /* inside them are not taken as comments.
+func tokenizeConfig(source string) []jsToken {
+	var tokens []jsToken
+	for i := 0; i < len(source); {
+		c := source[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case strings.HasPrefix(source[i:], "//"):
+			end := strings.IndexByte(source[i:], '\n')
+			if end < 0 {
+				end = len(source) - i
+			}
+			i += end
+		case strings.HasPrefix(source[i:], "/*"):
+			end := strings.Index(source[i+2:], "*/")
+			if end < 0 {
+				return tokens
+			}
+			i += end + 4
+		case c == '"' || c == '\'' || c == '`':
+			end, dynamic, closed := stringEnd(source, i)
+			kind := byte('s')
+			if !closed {
+				kind = 'u'
+			} else if dynamic {
+				kind = 't'
+			}
+			tokens = append(tokens, jsToken{kind, source[i:end]})
+			i = end
+		case isWordByte(c):
+			end := i
+			for end < len(source) && isWordByte(source[end]) {
+				end++
+			}
+			tokens = append(tokens, jsToken{'w', source[i:end]})
+			i = end
+		default:
+			tokens = append(tokens, jsToken{'p', source[i : i+1]})
+			i++
+		}
+	}
+	return tokens
+}
+
+// stringEnd returns the offset after the string literal starting at start,
+// whether it is a template literal with substitutions and whether it has its
+// closing quote.
+func stringEnd(source string, start int) (end int, dynamic, closed bool) {
+	quote := source[start]
+	depth := 0
+	for i := start + 1; i < len(source); i++ {
+		switch c := source[i]; {
+		case c == '\\':
+			i++
+		case depth > 0:
+			// ${} 中的內容直到對應的 } 為止
+			if c == '{' {
+				depth++
+			} else if c == '}' {
+				depth--
+			}
+		case quote == '`' && c == '$' && i+1 < len(source) && source[i+1] == '{':
+			dynamic = true
+			depth = 1
+			i++
+		case c == quote:
+			return i + 1, dynamic, true
+		case c == '\n' && quote != '`':
+			return i, dynamic, false
+		}
+	}
+	return len(source), dynamic, false
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c == '$' || c == '.' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// parseGolteConfig 容錯地讀取 default export 物件中的字串、數字與布林選項，支援單引號、雙引號、樣板字串與註解。
+// unread 是值無法讀取的選項，例如運算式、其他變數或沒有結尾引號的字串
+func parseGolteConfig(source string) (raw map[string]any, unread []string) {
+	raw = map[string]any{}
+	tokens := configObject(tokenizeConfig(source))
+	depth := 0
+	for i, tok := range tokens {
+		switch tok.text {
+		case "{":
+			depth++
+			continue
+		case "}":
+			depth--
+			continue
+		}
+		if i+1 >= len(tokens) || tokens[i+1].text != ":" || (tok.kind != 'w' && tok.kind != 's') || depth != 1 {
+			continue
+		}
+		key := tok.text
+		if tok.kind == 's' {
+			var ok bool
+			if key, ok = unquote(key); !ok {
+				continue
+			}
+		}
+		if value, ok := simpleValue(tokens[i+2:]); ok {
+			raw[key] = value
+		} else if i+2 < len(tokens) && tokens[i+2].text != "{" && tokens[i+2].text != "[" {
+			// 物件與陣列不是 golte 的路徑選項，不需要警告
+			unread = append(unread, key)
+		}
+	}
+	return raw, unread
+}
+
+// configObject returns the tokens of the object literal that follows
+// `export default` or `defineConfig(`, from its { to its }, or nil if there is none.
+// Objects assigned to other variables are not options.
+func configObject(tokens []jsToken) []jsToken {
+	for i := 0; i+1 < len(tokens); i++ {
+		start := -1
+		switch {
+		case tokens[i].text == "export" && tokens[i+1].text == "default" && i+2 < len(tokens) && tokens[i+2].text == "{":
+			start = i + 2
+		case tokens[i].text == "defineConfig" && tokens[i+1].text == "(" && i+2 < len(tokens) && tokens[i+2].text == "{":
+			start = i + 2
+		}
+		if start < 0 {
+			continue
+		}
+		depth := 0
+		for end := start; end < len(tokens); end++ {
+			switch tokens[end].text {
+			case "{":
+				depth++
+			case "}":
+				depth--
+				if depth == 0 {
+					return tokens[start : end+1]
+				}
+			}
+		}
+		// 物件沒有結尾時讀取到檔案結束，例如編輯到一半的設定檔
+		return tokens[start:]
+	}
+	return nil
+}
+
+// simpleValue reads a string, number or boolean literal followed by the end of
+// the property.
+func simpleValue(tokens []jsToken) (any, bool) {
+	if len(tokens) == 0 || len(tokens) > 1 && tokens[1].text != "," && tokens[1].text != "}" && tokens[1].text != ";" {
+		return nil, false
+	}
+	tok := tokens[0]
+	switch tok.kind {
+	case 's':
+		return unquote(tok.text)
+	case 'w':
+		switch tok.text {
+		case "true":
+			return true, true
+		case "false":
+			return false, true
+		}
+		if number, err := strconv.ParseFloat(tok.text, 64); err == nil {
+			return number, true
+		}
+	}
+	return nil, false
+}
+
+// unquote returns the value of a string literal, or false if it has no closing quote.
+func unquote(literal string) (string, bool) {
+	if len(literal) < 2 || literal[len(literal)-1] != literal[0] {
+		return "", false
+	}
+	switch literal[0] {
+	case '`':
+		return literal[1 : len(literal)-1], true
+	case '\'':
+		body := strings.ReplaceAll(literal[1:len(literal)-1], `\'`, `'`)
+		literal = `"` + strings.ReplaceAll(body, `"`, `\"`) + `"`
+	}
+	if value, err := strconv.Unquote(literal); err == nil {
+		return value, true
+	}
+	return literal[1 : len(literal)-1], true
+}
+
+// TemplatePath returns the absolute path of the HTML template.
+func (c *GolteConfig) TemplatePath() string {
+	return filepath.Join(c.ProjectPath, c.Template)
+}
+
+// SrcPath returns the absolute path of the Svelte source directory.
+func (c *GolteConfig) SrcPath() string {
+	return filepath.Join(c.ProjectPath, c.SrcDir)
+}
+
+// OutPath returns the absolute path of the build output directory.
+func (c *GolteConfig) OutPath() string {
+	return filepath.Join(c.ProjectPath, c.OutDir)
+}
@@ -0,0 +1,125 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseGolteConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		raw    map[string]any
+		unread []string
+	}{
+		{
+			name:   "double slash in string",
+			source: `export default { outDir: "a//b", template: "src/app.html" }`,
+			raw:    map[string]any{"outDir": "a//b", "template": "src/app.html"},
+		},
+		{
+			name:   "block comment start in string",
+			source: `export default { srcDir: "src/*.svelte", outDir: 'out/' }`,
+			raw:    map[string]any{"srcDir": "src/*.svelte", "outDir": "out/"},
+		},
+		{
+			name:   "url",
+			source: `export default { appPath: "http://localhost/app" }`,
+			raw:    map[string]any{"appPath": "http://localhost/app"},
+		},
+		{
+			name: "comments",
+			source: `// golte config
+export default {
+	/* srcDir: "ignored", */
+	srcDir: "src/", // trailing comment
+	// outDir: "ignored",
+	outDir: "dist/",
+};`,
+			raw: map[string]any{"srcDir": "src/", "outDir": "dist/"},
+		},
+		{
+			name:   "quoted keys, escapes and template literal",
+			source: "export default { \"template\": 'it\\'s.html', srcDir: `web/` }",
+			raw:    map[string]any{"template": "it's.html", "srcDir": "web/"},
+		},
+		{
+			name:   "numbers and booleans",
+			source: `export default { port: 3000, ssr: true, hydrate: false }`,
+			raw:    map[string]any{"port": 3000.0, "ssr": true, "hydrate": false},
+		},
+		{
+			name:   "template literal with substitution",
+			source: "const base = 'web'; export default { srcDir: `${base}/src`, outDir: 'build/' }",
+			raw:    map[string]any{"outDir": "build/"},
+			unread: []string{"srcDir"},
+		},
+		{
+			name:   "expression",
+			source: `export default { outDir: base + '/build', template: "web/app.html" }`,
+			raw:    map[string]any{"template": "web/app.html"},
+			unread: []string{"outDir"},
+		},
+		{
+			name:   "nested object",
+			source: `export default { vite: { base: "/app/", plugins: [svelte()] }, srcDir: "web/" }`,
+			raw:    map[string]any{"srcDir": "web/"},
+			unread: nil,
+		},
+		{
+			name: "other objects",
+			source: `const extra = { outDir: "x" };
+export default {
+	outDir: "build/",
+};
+const after = { srcDir: "y" };`,
+			raw: map[string]any{"outDir": "build/"},
+		},
+		{
+			name: "defineConfig",
+			source: `import { defineConfig } from "golte/config";
+const paths = { srcDir: "x" };
+export default defineConfig({ srcDir: "src/", vite: { outDir: "y" } });`,
+			raw: map[string]any{"srcDir": "src/"},
+		},
+		{
+			name:   "no default export",
+			source: `const config = { srcDir: "src/" };`,
+			raw:    map[string]any{},
+		},
+		{
+			name:   "unterminated string at the end",
+			source: `export default { srcDir: "`,
+			raw:    map[string]any{},
+			unread: []string{"srcDir"},
+		},
+		{
+			name:   "unterminated string before a newline",
+			source: "export default { srcDir: '\n}",
+			raw:    map[string]any{},
+			unread: []string{"srcDir"},
+		},
+		{
+			name:   "unterminated string with escaped quote",
+			source: `export default { outDir: "build/", template: "web/app.html\"`,
+			raw:    map[string]any{"outDir": "build/"},
+			unread: []string{"template"},
+		},
+		{
+			name:   "unterminated quoted key",
+			source: `export default { outDir: "build/", "srcDir`,
+			raw:    map[string]any{"outDir": "build/"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw, unread := parseGolteConfig(tt.source)
+			if !reflect.DeepEqual(raw, tt.raw) {
+				t.Errorf("raw = %v, want %v", raw, tt.raw)
+			}
+			if !reflect.DeepEqual(unread, tt.unread) {
+				t.Errorf("unread = %v, want %v", unread, tt.unread)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// rootMarkers are the files that mark the root of a project.
+var rootMarkers = []string{"golte.config.ts", FileName, "go.mod"}
+
+// FindRoot walks up from dir to the first directory containing golte.config.ts,
+// golte-cli.toml or go.mod.
+func FindRoot(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	start := dir
+	for {
+		for _, marker := range rootMarkers {
+			if _, err := os.Stat(filepath.Join(dir, marker)); err == nil {
+				return dir, nil
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf("no golte.config.ts, %s or go.mod found in %s or any parent directory", FileName, start)
+		}
+		dir = parent
+	}
+}
+
+// ModulePath returns the module path declared in the project's go.mod, or "".
+func ModulePath(projectPath string) string {
+	file, err := os.Open(filepath.Join(projectPath, "go.mod"))
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "module" {
+			return strings.Trim(fields[1], `"`)
+		}
+	}
+	return ""
+}
+
+var majorVersionSuffix = regexp.MustCompile(`^v[0-9]+$`)
+
+// binaryName 取 module path 的最後一段作為執行檔名稱，略過 /v2 之類的版本後綴
+func binaryName(modulePath string) string {
+	name := path.Base(modulePath)
+	if majorVersionSuffix.MatchString(name) {
+		name = path.Base(path.Dir(modulePath))
+	}
+	if name == "." || name == "/" {
+		return ""
+	}
+	return name
+}
@@ -0,0 +1,91 @@
+package create
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// commandFiles are the files the setup commands (`bun init`, `bun install`,
+// `go get`) may change in an existing directory.
+var commandFiles = []string{"package.json", "tsconfig.json", "bun.lockb", "bun.lock", ".gitignore", "README.md", "index.ts", "go.mod", "go.sum"}
+
+// backup holds copies of the existing files that creating a project in an
+// existing directory overwrites or removes, so a failed create can restore them.
+type backup struct {
+	// dir is the directory with the copies, root the project directory.
+	dir  string
+	root string
+	// files are the backed up paths, relative to root.
+	files []string
+}
+
+// backupFiles copies the files in root that exist and are listed in files to a
+// temporary directory outside root.
+func backupFiles(root string, files []string) (*backup, error) {
+	dir, err := os.MkdirTemp("", "golte-cli-backup-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create backup directory: %v", err)
+	}
+	b := &backup{dir: dir, root: root}
+	for _, file := range files {
+		info, err := os.Lstat(filepath.Join(root, file))
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		if err := copyFile(filepath.Join(root, file), filepath.Join(dir, file), info.Mode().Perm()); err != nil {
+			b.discard()
+			return nil, fmt.Errorf("failed to back up %s: %v", file, err)
+		}
+		b.files = append(b.files, file)
+	}
+	return b, nil
+}
+
+// restore writes the backed up files back to the project directory and
+// removes the backup once all of them are restored.
+func (b *backup) restore() error {
+	var failed []string
+	for _, file := range b.files {
+		dest := filepath.Join(b.root, file)
+		info, err := os.Stat(filepath.Join(b.dir, file))
+		if err == nil {
+			err = copyFile(filepath.Join(b.dir, file), dest, info.Mode().Perm())
+		}
+		if err != nil {
+			failed = append(failed, file)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to restore %v, the original files are kept in %s", failed, b.dir)
+	}
+	b.discard()
+	return nil
+}
+
+// discard removes the backup.
+func (b *backup) discard() {
+	os.RemoveAll(b.dir)
+}
+
+// copyFile copies src to dest with perm, creating the parent directories of dest.
+func copyFile(src, dest string, perm os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+	out, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,109 @@
+package create
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// ConflictMode decides what happens to existing files when a project is
+// created in an existing directory.
+type ConflictMode int
+
+const (
+	// ConflictFail refuses to create the project if any file would be overwritten.
+	ConflictFail ConflictMode = iota
+	// ConflictOverwrite overwrites existing files.
+	ConflictOverwrite
+	// ConflictSkip keeps existing files and only writes the missing ones.
+	ConflictSkip
+)
+
+// ConflictError is returned by CreateProject when existing files would be overwritten.
+type ConflictError struct {
+	// Files are the conflicting paths, relative to the project directory.
+	Files []string
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%d existing files would be overwritten:\n  %s", len(e.Files), strings.Join(e.Files, "\n  "))
+}
+
+// plannedFiles returns the paths, relative to the project directory, of the
+// files the scaffolding and the template write. go.mod is included unless an
+// existing module is merged.
+func plannedFiles(projectPath string, opts Options) ([]string, error) {
+	var files []string
+	for _, dest := range sourceFiles(opts.TemplateData) {
+		files = append(files, dest)
+	}
+	err := fs.WalkDir(opts.Templates, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Name() == ".git" || name == ManifestFile {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !d.IsDir() {
+			files = append(files, filepath.FromSlash(strings.TrimSuffix(name, ".tmpl")))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template: %v", err)
+	}
+	if !mergeModule(projectPath, opts) {
+		files = append(files, "go.mod")
+	}
+	slices.Sort(files)
+	return slices.Compact(files), nil
+}
+
+// Conflicts returns the files CreateProject would overwrite in projectPath.
+func Conflicts(projectPath string, opts Options) ([]string, error) {
+	files, err := plannedFiles(projectPath, opts)
+	if err != nil {
+		return nil, err
+	}
+	var conflicts []string
+	for _, file := range files {
+		if _, err := os.Stat(filepath.Join(projectPath, file)); err == nil {
+			conflicts = append(conflicts, file)
+		}
+	}
+	return conflicts, nil
+}
+
+// mergeModule reports whether the project is added to the Go module that
+// already exists in projectPath instead of running `go mod init`.
+func mergeModule(projectPath string, opts Options) bool {
+	if !opts.Merge && opts.OnConflict != ConflictSkip {
+		return false
+	}
+	_, err := os.Stat(filepath.Join(projectPath, "go.mod"))
+	return err == nil
+}
+
+// skipSet returns the conflicting files that must not be written.
+func skipSet(conflicts []string, opts Options) map[string]bool {
+	skip := map[string]bool{}
+	if opts.OnConflict == ConflictSkip {
+		for _, file := range conflicts {
+			skip[file] = true
+		}
+	}
+	return skip
+}
+
+// isSkipped reports whether the template file name, relative to the template
+// root, is written to a path in skip.
+func isSkipped(skip map[string]bool, name string) bool {
+	return skip[filepath.FromSlash(strings.TrimSuffix(path.Clean(name), ".tmpl"))]
+}
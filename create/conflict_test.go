@@ -0,0 +1,165 @@
+package create
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+// testTemplates is a template with a manifest, a .git directory and a file
+// replacing the scaffolded main.go.
+var testTemplates = fstest.MapFS{
+	ManifestFile:                 {Data: []byte("[vars]\n")},
+	".git/HEAD":                  {Data: []byte("ref: refs/heads/main\n")},
+	"golte.config.ts.tmpl":       {Data: []byte("export default {}\n")},
+	"main.go.tmpl":               {Data: []byte("package main\n")},
+	"web/app.html":               {Data: []byte("<html></html>\n")},
+	"web/pages/App.svelte.tmpl":  {Data: []byte("<h1>{{.ProjectName}}</h1>\n")},
+	"web/components/.gitkeep":    {Data: nil},
+	"web/layouts/Main.svelte":    {Data: []byte("<slot />\n")},
+	"web/layouts/sub/Sub.svelte": {Data: []byte("<slot />\n")},
+}
+
+func TestPlannedFiles(t *testing.T) {
+	templateFiles := []string{
+		"golte.config.ts",
+		"main.go",
+		filepath.Join("web", "app.html"),
+		filepath.Join("web", "components", ".gitkeep"),
+		filepath.Join("web", "layouts", "Main.svelte"),
+		filepath.Join("web", "layouts", "sub", "Sub.svelte"),
+		filepath.Join("web", "pages", "App.svelte"),
+	}
+	tests := []struct {
+		name     string
+		opts     Options
+		existing []string
+		want     []string
+	}{
+		{
+			name: "new module",
+			opts: Options{TemplateData: TemplateData{Router: "gin"}},
+			want: append([]string{"go.mod", "package.json", filepath.Join("router", "defineRoutes.go"), filepath.Join("router", "router.go")}, templateFiles...),
+		},
+		{
+			name: "gitignore feature",
+			opts: Options{TemplateData: TemplateData{Router: "chi", Features: []string{"gitignore"}}},
+			want: append([]string{".gitignore", "go.mod", "package.json", filepath.Join("router", "defineRoutes.go"), filepath.Join("router", "router.go")}, templateFiles...),
+		},
+		{
+			name:     "merged module",
+			opts:     Options{Merge: true, TemplateData: TemplateData{Router: "echo"}},
+			existing: []string{"go.mod"},
+			want:     append([]string{"package.json", filepath.Join("router", "defineRoutes.go"), filepath.Join("router", "router.go")}, templateFiles...),
+		},
+		{
+			name: "merge without go.mod",
+			opts: Options{Merge: true, TemplateData: TemplateData{Router: "echo"}},
+			want: append([]string{"go.mod", "package.json", filepath.Join("router", "defineRoutes.go"), filepath.Join("router", "router.go")}, templateFiles...),
+		},
+		{
+			name:     "skip merges the module",
+			opts:     Options{OnConflict: ConflictSkip, TemplateData: TemplateData{Router: "fiber"}},
+			existing: []string{"go.mod"},
+			want:     append([]string{"package.json", filepath.Join("router", "defineRoutes.go"), filepath.Join("router", "router.go")}, templateFiles...),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projectPath := t.TempDir()
+			touch(t, projectPath, tt.existing...)
+			tt.opts.Templates = testTemplates
+			got, err := plannedFiles(projectPath, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := sorted(tt.want)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("plannedFiles() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestConflicts(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     Options
+		existing []string
+		want     []string
+	}{
+		{
+			name: "empty directory",
+			opts: Options{TemplateData: TemplateData{Router: "gin"}},
+		},
+		{
+			name:     "unrelated files",
+			opts:     Options{TemplateData: TemplateData{Router: "gin"}},
+			existing: []string{"notes.txt", filepath.Join("web", "other.svelte")},
+		},
+		{
+			name:     "overwritten files",
+			opts:     Options{TemplateData: TemplateData{Router: "gin"}},
+			existing: []string{"go.mod", "main.go", filepath.Join("router", "router.go"), filepath.Join("web", "app.html"), "notes.txt"},
+			want:     []string{"go.mod", "main.go", filepath.Join("router", "router.go"), filepath.Join("web", "app.html")},
+		},
+		{
+			name:     "merged go.mod is not a conflict",
+			opts:     Options{Merge: true, TemplateData: TemplateData{Router: "gin"}},
+			existing: []string{"go.mod", "package.json"},
+			want:     []string{"package.json"},
+		},
+		{
+			name:     "gitignore only with the feature",
+			opts:     Options{TemplateData: TemplateData{Router: "chi"}},
+			existing: []string{".gitignore"},
+		},
+		{
+			name:     "gitignore feature",
+			opts:     Options{TemplateData: TemplateData{Router: "chi", Features: []string{"gitignore"}}},
+			existing: []string{".gitignore"},
+			want:     []string{".gitignore"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projectPath := t.TempDir()
+			touch(t, projectPath, tt.existing...)
+			tt.opts.Templates = testTemplates
+			got, err := Conflicts(projectPath, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := sorted(tt.want); !reflect.DeepEqual(got, want) {
+				t.Errorf("Conflicts() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+// touch creates the files under root.
+func touch(t *testing.T, root string, files ...string) {
+	t.Helper()
+	for _, file := range files {
+		path := filepath.Join(root, file)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// sorted returns files sorted like plannedFiles, or nil if there are none.
+func sorted(files []string) []string {
+	if len(files) == 0 {
+		return nil
+	}
+	files = append([]string(nil), files...)
+	slices.Sort(files)
+	return files
+}
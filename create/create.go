@@ -1,117 +1,245 @@
 package create
 
 import (
-	"embed"
 	"fmt"
-	"log"
+	"io/fs"
 	"os"
 	"os/exec"
 	"path"
 	"path/filepath"
+	"slices"
 	"strings"
+
+	"github.com/TimLai666/golte-cli/config"
 )
 
-func CreateProject(projectName string, templates embed.FS, inCurrentDir bool, isSveltigo bool, bunPath string) {
-	if bunPath == "" {
-		bunPath = "bun"
+// Options configures CreateProject.
+type Options struct {
+	// BaseDir is the directory the project directory is created in.
+	BaseDir string
+	// InCurrentDir creates the project in BaseDir itself.
+	InCurrentDir bool
+	// Templates holds the template files, rooted at the template directory.
+	// Files ending in .tmpl are rendered with TemplateData. They are written
+	// after the scaffolded main.go and router package, so a template can replace them.
+	Templates fs.FS
+	BunPath   string
+	// PostCreate are shell commands run in the project directory at the end.
+	PostCreate []string
+	// KeepOnFailure keeps the partially created project when a step fails.
+	KeepOnFailure bool
+	// OnConflict decides what happens to existing files with InCurrentDir.
+	OnConflict ConflictMode
+	// Merge adds the project to the Go module that already exists in BaseDir
+	// instead of running `go mod init`. ModulePath is taken from its go.mod.
+	Merge bool
+	TemplateData
+
+	// replaceModule removes the existing go.mod and go.sum right before
+	// `go mod init`, when they are overwritten with ConflictOverwrite.
+	replaceModule bool
+}
+
+// CreateProject creates the project opts.ProjectName inside opts.BaseDir, or in
+// opts.BaseDir itself if opts.InCurrentDir is set.
+//
+// A new project directory is populated in a temporary directory next to it and
+// only renamed into place once every step succeeded. With InCurrentDir,
+// existing files are handled according to OnConflict, a *ConflictError listing
+// them is returned by default. The files that are overwritten are backed up
+// first; on failure the files and directories created in BaseDir are removed
+// and the backed up files restored. KeepOnFailure leaves them in place for
+// debugging.
+func CreateProject(opts Options) (err error) {
+	if opts.BunPath == "" {
+		opts.BunPath = "bun"
 	}
+
+	// rollback 移除建立失敗時已產生的檔案，createdPath 是目前存放專案檔案的目錄
+	var (
+		rollback    func()
+		createdPath string
+		keepMessage string
+		saved       *backup
+	)
+	defer func() {
+		if err == nil && saved != nil {
+			saved.discard()
+		}
+		if err == nil || rollback == nil {
+			return
+		}
+		if opts.KeepOnFailure {
+			fmt.Fprintf(os.Stderr, "Keeping the partially created project in %s\n", createdPath)
+			if keepMessage != "" {
+				fmt.Fprintf(os.Stderr, "Not restoring the overwritten files, %s\n", keepMessage)
+			}
+			return
+		}
+		rollback()
+	}()
+
 	var projectPath string
-	if inCurrentDir {
-		projectPath = "."
+	if opts.InCurrentDir {
+		projectPath = opts.BaseDir
+		existing, err := os.ReadDir(projectPath)
+		if err != nil {
+			return fmt.Errorf("failed to read project directory: %v", err)
+		}
+
+		// 先檢查會被覆蓋的檔案，沒有任何變更前就失敗
+		conflicts, err := Conflicts(projectPath, opts)
+		if err != nil {
+			return err
+		}
+		if len(conflicts) > 0 && opts.OnConflict == ConflictFail {
+			return &ConflictError{Files: conflicts}
+		}
+		planned, err := plannedFiles(projectPath, opts)
+		if err != nil {
+			return err
+		}
+		created := newPaths(projectPath, planned)
+
+		// 備份會被覆蓋或被命令修改的檔案，失敗時還原
+		saved, err = backupFiles(projectPath, append(conflicts, commandFiles...))
+		if err != nil {
+			return err
+		}
+		rollback = func() {
+			removePaths(projectPath, created)
+			removeCreated(projectPath, existing)
+			if err := saved.restore(); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			}
+		}
+		keepMessage = fmt.Sprintf("the original files are backed up in %s", saved.dir)
+		createdPath = projectPath
+
+		if mergeModule(projectPath, opts) {
+			opts.ModulePath = config.ModulePath(projectPath)
+			if opts.ModulePath == "" {
+				return fmt.Errorf("failed to read the module path from go.mod")
+			}
+			opts.Merge = true
+		} else {
+			// 覆蓋 go.mod 時重新初始化模組，舊的檔案在 go mod init 前才移除，失敗時從備份還原
+			opts.replaceModule = slices.Contains(conflicts, "go.mod")
+		}
+		if err := populateProject(projectPath, opts, skipSet(conflicts, opts)); err != nil {
+			return err
+		}
 	} else {
-		projectPath = filepath.Join("./", projectName)
+		projectPath = filepath.Join(opts.BaseDir, opts.ProjectName)
 		if _, err := os.Stat(projectPath); !os.IsNotExist(err) {
-			log.Fatalf("Project '%s' already exists", projectName)
+			return fmt.Errorf("project '%s' already exists", opts.ProjectName)
 		}
 
-		err := os.MkdirAll(projectPath, 0755)
+		if err := os.MkdirAll(opts.BaseDir, 0755); err != nil {
+			return fmt.Errorf("failed to create directory %s: %v", opts.BaseDir, err)
+		}
+		// 在同一個目錄下建立暫存目錄，完成後才能以 rename 移到正式位置
+		stagingPath, err := os.MkdirTemp(opts.BaseDir, "."+opts.ProjectName+"-creating-")
 		if err != nil {
-			log.Fatalf("Failed to create project directory: %v", err)
+			return fmt.Errorf("failed to create project directory: %v", err)
+		}
+		rollback = func() { os.RemoveAll(stagingPath) }
+		createdPath = stagingPath
+		if err := os.Chmod(stagingPath, 0755); err != nil {
+			return fmt.Errorf("failed to create project directory: %v", err)
 		}
-	}
-
-	err := copyTemplateFiles(projectPath, "templates", templates)
-	if err != nil {
-		log.Fatalf("Failed to copy template files: %v", err)
-	}
 
-	// put main.go content
-	mainContent := strings.Replace(mainContentTemplate, "{{projectName}}", projectName, -1)
-	err = os.WriteFile(filepath.Join(projectPath, "main.go"), []byte(mainContent), 0644)
-	if err != nil {
-		log.Fatalf("Failed to write main.go file: %v", err)
+		opts.Merge = false
+		if err := populateProject(stagingPath, opts, nil); err != nil {
+			return err
+		}
+		if err := os.Rename(stagingPath, projectPath); err != nil {
+			return fmt.Errorf("failed to move project into place: %v", err)
+		}
+		rollback = func() { os.RemoveAll(projectPath) }
+		createdPath = projectPath
 	}
+	return runPostCreate(projectPath, opts)
+}
 
-	// make router directory
-	err = os.MkdirAll(filepath.Join(projectPath, "router"), 0755)
+// populateProject writes the project files into projectPath, except the files
+// in skip, and installs its dependencies.
+func populateProject(projectPath string, opts Options, skip map[string]bool) error {
+	// put main.go, package.json and router package content
+	err := writeSourceFiles(projectPath, opts.TemplateData, skip)
 	if err != nil {
-		log.Fatalf("Failed to create router directory: %v", err)
+		return err
 	}
 
-	// put router.go content
-	var ginContent string
-	if isSveltigo {
-		ginContent = strings.Replace(ginContentTemplate_sveltigo, "{{projectName}}", projectName, -1)
-	} else {
-		ginContent = strings.Replace(ginContentTemplate, "{{projectName}}", projectName, -1)
-	}
-	err = os.WriteFile(filepath.Join(projectPath, "router", "router.go"), []byte(ginContent), 0644)
+	err = copyTemplateFiles(projectPath, ".", opts.Templates, opts.TemplateData, skip)
 	if err != nil {
-		log.Fatalf("Failed to write router.go file: %v", err)
+		return fmt.Errorf("failed to copy template files: %v", err)
 	}
 
-	// put defineRoutes.go content
-	var defineRoutesContentStr string
-	if isSveltigo {
-		defineRoutesContentStr = strings.Replace(defineRoutesSveltigoContent, "{{projectName}}", projectName, -1)
-	} else {
-		defineRoutesContentStr = strings.Replace(defineRoutesContent, "{{projectName}}", projectName, -1)
-	}
-	err = os.WriteFile(filepath.Join(projectPath, "router", "defineRoutes.go"), []byte(defineRoutesContentStr), 0644)
+	// make sure golte.config.ts points to the scaffolded files
+	golteConfig, err := config.LoadGolteConfig(projectPath, opts.BunPath)
 	if err != nil {
-		log.Fatalf("Failed to write router.go file: %v", err)
+		return fmt.Errorf("failed to load %s: %v", config.GolteConfigFile, err)
 	}
-
-	// put package.json content
-	packageJsonContent := strings.Replace(packageJsonContentTemplate, "{{projectName}}", projectName, -1)
-	err = os.WriteFile(filepath.Join(projectPath, "package.json"), []byte(packageJsonContent), 0644)
-	if err != nil {
-		log.Fatalf("Failed to write package.json file: %v", err)
+	for _, path := range []string{golteConfig.TemplatePath(), golteConfig.SrcPath()} {
+		if _, err := os.Stat(path); err != nil {
+			return fmt.Errorf("template does not match %s: %v", config.GolteConfigFile, err)
+		}
 	}
 
-	// Initialize Go module
-	cmd := exec.Command("go", "mod", "init", projectName)
-	cmd.Dir = projectPath
-	if output, err := cmd.CombinedOutput(); err != nil {
-		log.Fatalf("Failed to initialize Go module: %v\n%s", err, output)
+	// Initialize Go module, unless golte is added to an existing one
+	if !opts.Merge {
+		if opts.replaceModule {
+			for _, file := range []string{"go.mod", "go.sum"} {
+				if err := os.Remove(filepath.Join(projectPath, file)); err != nil && !os.IsNotExist(err) {
+					return fmt.Errorf("failed to remove %s: %v", file, err)
+				}
+			}
+		}
+		cmd := exec.Command("go", "mod", "init", opts.ModulePath)
+		cmd.Dir = projectPath
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to initialize Go module: %v\n%s", err, output)
+		}
 	}
 
 	// Run bun init
-	bunCmd := exec.Command(bunPath, "init", "-y")
+	bunCmd := exec.Command(opts.BunPath, "init", "-y")
 	bunCmd.Dir = projectPath
 	if output, err := bunCmd.CombinedOutput(); err != nil {
-		log.Fatalf("Failed to initialize bun: %v\n%s", err, output)
+		return fmt.Errorf("failed to initialize bun: %v\n%s", err, output)
 	}
 
-	// Get Gin package
-	getCmd := exec.Command("go", "get", "-u", "github.com/gin-gonic/gin")
-	getCmd.Dir = projectPath
-	if output, err := getCmd.CombinedOutput(); err != nil {
-		log.Fatalf("Failed to get Gin package: %v\n%s", err, output)
+	// Get router package
+	if dependency := routerTemplates[opts.Router].dependency; dependency != "" {
+		getCmd := exec.Command("go", "get", "-u", dependency)
+		getCmd.Dir = projectPath
+		if output, err := getCmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to get %s package: %v\n%s", opts.Router, err, output)
+		}
 	}
 
 	// Get Golte package
-	getCmd = exec.Command("go", "get", "-u", "github.com/nichady/golte")
+	getCmd := exec.Command("go", "get", "-u", "github.com/nichady/golte")
 	getCmd.Dir = projectPath
 	if output, err := getCmd.CombinedOutput(); err != nil {
-		log.Fatalf("Failed to get Golte package: %v\n%s", err, output)
+		return fmt.Errorf("failed to get Golte package: %v\n%s", err, output)
+	}
+
+	// Get Sveltigo package
+	if opts.IsSveltigo() {
+		getCmd = exec.Command("go", "get", "-u", "github.com/HazelnutParadise/sveltigo")
+		getCmd.Dir = projectPath
+		if output, err := getCmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to get Sveltigo package: %v\n%s", err, output)
+		}
 	}
 
 	// Install bun package
-	bunInstallCmd := exec.Command(bunPath, "install", "golte@latest")
+	bunInstallCmd := exec.Command(opts.BunPath, "install", "golte@latest")
 	bunInstallCmd.Dir = projectPath
 	if output, err := bunInstallCmd.CombinedOutput(); err != nil {
-		log.Fatalf("Failed to install bun package: %v\n%s", err, output)
+		return fmt.Errorf("failed to install bun package: %v\n%s", err, output)
 	}
 
 	// bunInstallCmd = exec.Command("bun", "install", "svelte@latest")
@@ -119,34 +247,110 @@ func CreateProject(projectName string, templates embed.FS, inCurrentDir bool, is
 	// if output, err := bunInstallCmd.CombinedOutput(); err != nil {
 	// 	log.Fatalf("Failed to install bun package: %v\n%s", err, output)
 	// }
+	return nil
+}
+
+// runPostCreate runs the post-create commands of the template in projectPath.
+func runPostCreate(projectPath string, opts Options) error {
+	for _, command := range opts.PostCreate {
+		rendered, err := renderString("postCreate", command, opts.TemplateData)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Running %s\n", rendered)
+		args := shellCommand(string(rendered))
+		postCmd := exec.Command(args[0], args[1:]...)
+		postCmd.Dir = projectPath
+		postCmd.Stdout = os.Stdout
+		postCmd.Stderr = os.Stderr
+		if err := postCmd.Run(); err != nil {
+			return fmt.Errorf("post-create command %q failed: %v", rendered, err)
+		}
+	}
+	return nil
+}
+
+// newPaths returns the files in planned that do not exist in dir yet, followed
+// by the directories that would be created for them, deepest first.
+func newPaths(dir string, planned []string) []string {
+	var files, dirs []string
+	for _, file := range planned {
+		if _, err := os.Stat(filepath.Join(dir, file)); err == nil {
+			continue
+		}
+		files = append(files, file)
+		for parent := filepath.Dir(file); parent != "."; parent = filepath.Dir(parent) {
+			if _, err := os.Stat(filepath.Join(dir, parent)); err == nil {
+				break
+			}
+			if !slices.Contains(dirs, parent) {
+				dirs = append(dirs, parent)
+			}
+		}
+	}
+	slices.SortFunc(dirs, func(a, b string) int { return len(b) - len(a) })
+	return append(files, dirs...)
 }
 
-func copyTemplateFiles(destPath, templatePath string, templates embed.FS) error {
-	entries, err := templates.ReadDir(templatePath)
+// removePaths removes paths, relative to dir. Directories are only removed if empty.
+func removePaths(dir string, paths []string) {
+	for _, p := range paths {
+		os.Remove(filepath.Join(dir, p))
+	}
+}
+
+// removeCreated removes the entries of dir that are not in existing.
+func removeCreated(dir string, existing []os.DirEntry) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		if !slices.ContainsFunc(existing, func(e os.DirEntry) bool { return e.Name() == entry.Name() }) {
+			os.RemoveAll(filepath.Join(dir, entry.Name()))
+		}
+	}
+}
+
+// copyTemplateFiles copies the directory templatePath of templates into destPath,
+// skipping the manifest and .git.
+func copyTemplateFiles(destPath, templatePath string, templates fs.FS, data TemplateData, skip map[string]bool) error {
+	entries, err := fs.ReadDir(templates, templatePath)
 	if err != nil {
 		return fmt.Errorf("failed to read template directory: %v", err)
 	}
 
 	for _, entry := range entries {
+		if entry.Name() == ".git" || (templatePath == "." && entry.Name() == ManifestFile) {
+			continue
+		}
 		sourcePath := path.Join(templatePath, entry.Name())
 		destFilePath := filepath.Join(destPath, entry.Name())
+		if !entry.IsDir() && isSkipped(skip, sourcePath) {
+			continue
+		}
 
 		if entry.IsDir() {
 			err = os.MkdirAll(destFilePath, 0755)
 			if err != nil {
 				return fmt.Errorf("failed to create directory %s: %v", destFilePath, err)
 			}
-			err = copyTemplateFiles(destFilePath, sourcePath, templates)
+			err = copyTemplateFiles(destFilePath, sourcePath, templates, data, skip)
+			if err != nil {
+				return err
+			}
+		} else if strings.HasSuffix(entry.Name(), ".tmpl") {
+			err = writeTemplate(templates, sourcePath, strings.TrimSuffix(destFilePath, ".tmpl"), data)
 			if err != nil {
 				return err
 			}
 		} else {
-			data, err := templates.ReadFile(sourcePath)
+			content, err := fs.ReadFile(templates, sourcePath)
 			if err != nil {
 				return fmt.Errorf("failed to read template file %s: %v", sourcePath, err)
 			}
 
-			err = os.WriteFile(destFilePath, data, 0644)
+			err = os.WriteFile(destFilePath, content, 0644)
 			if err != nil {
 				return fmt.Errorf("failed to write file %s: %v", destFilePath, err)
 			}
@@ -0,0 +1,222 @@
+package create
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// ManifestFile is the manifest at the root of a custom template. It is not
+// copied into the project.
+const ManifestFile = "golte-template.toml"
+
+// Manifest describes a custom project template.
+type Manifest struct {
+	Name        string `toml:"name"`
+	Description string `toml:"description"`
+	// PostCreate are shell commands run in the project directory once it has
+	// been created. They are rendered with TemplateData like .tmpl files.
+	PostCreate []string `toml:"postCreate"`
+	// Variables are available to templates as {{.Vars.<name>}}.
+	Variables []Variable `toml:"variables"`
+}
+
+// Variable is a template variable declared in the manifest.
+type Variable struct {
+	Name string `toml:"name"`
+	// Prompt is the question asked when the value is not given with --var.
+	Prompt  string `toml:"prompt"`
+	Default string `toml:"default"`
+	// Required variables must end up with a non-empty value.
+	Required bool `toml:"required"`
+}
+
+// Template is a project template with the same layout as the embedded
+// templates/ directory.
+type Template struct {
+	// FS holds the template files, rooted at the template directory.
+	FS       fs.FS
+	Manifest Manifest
+	// close releases the extracted or opened archive.
+	close func() error
+}
+
+// variableName matches names usable as {{.Vars.<name>}}.
+var variableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// LoadTemplate opens a custom template from a directory or a .tar.gz, .tgz,
+// .tar or .zip archive. An archive whose only entry is a directory uses that
+// directory as the template root. Close must be called when done.
+func LoadTemplate(source string) (*Template, error) {
+	info, err := os.Stat(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open template: %v", err)
+	}
+
+	t := &Template{close: func() error { return nil }}
+	switch {
+	case info.IsDir():
+		t.FS = os.DirFS(source)
+	case strings.HasSuffix(source, ".zip"):
+		r, err := zip.OpenReader(source)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open template archive: %v", err)
+		}
+		t.FS, t.close = r, r.Close
+	case strings.HasSuffix(source, ".tar.gz"), strings.HasSuffix(source, ".tgz"), strings.HasSuffix(source, ".tar"):
+		dir, err := os.MkdirTemp("", "golte-template-")
+		if err != nil {
+			return nil, fmt.Errorf("failed to create temporary directory: %v", err)
+		}
+		if err := extractTar(source, dir); err != nil {
+			os.RemoveAll(dir)
+			return nil, fmt.Errorf("failed to extract template archive: %v", err)
+		}
+		t.FS, t.close = os.DirFS(dir), func() error { return os.RemoveAll(dir) }
+	default:
+		return nil, fmt.Errorf("unsupported template %s: expected a directory or a .tar.gz, .tgz, .tar or .zip archive", source)
+	}
+
+	if err := t.load(); err != nil {
+		t.Close()
+		return nil, err
+	}
+	return t, nil
+}
+
+// load descends into a single top-level directory and reads the manifest.
+func (t *Template) load() error {
+	entries, err := fs.ReadDir(t.FS, ".")
+	if err != nil {
+		return fmt.Errorf("failed to read template: %v", err)
+	}
+	if len(entries) == 1 && entries[0].IsDir() {
+		if t.FS, err = fs.Sub(t.FS, entries[0].Name()); err != nil {
+			return fmt.Errorf("failed to read template: %v", err)
+		}
+	}
+
+	content, err := fs.ReadFile(t.FS, ManifestFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %v", ManifestFile, err)
+	}
+	if err := toml.Unmarshal(content, &t.Manifest); err != nil {
+		return fmt.Errorf("failed to parse %s: %v", ManifestFile, err)
+	}
+	for _, v := range t.Manifest.Variables {
+		if !variableName.MatchString(v.Name) {
+			return fmt.Errorf("invalid variable name %q in %s", v.Name, ManifestFile)
+		}
+	}
+	return nil
+}
+
+// Close releases the resources held by the template.
+func (t *Template) Close() error {
+	return t.close()
+}
+
+// ResolveVariables returns the value of every variable in the manifest. Values
+// not in given are asked with prompt, if set, and otherwise use the default.
+func (t *Template) ResolveVariables(given map[string]string, prompt func(v Variable) (string, error)) (map[string]string, error) {
+	vars := map[string]string{}
+	for name, value := range given {
+		vars[name] = value
+	}
+	for _, v := range t.Manifest.Variables {
+		if _, ok := vars[v.Name]; !ok {
+			vars[v.Name] = v.Default
+			if prompt != nil {
+				value, err := prompt(v)
+				if err != nil {
+					return nil, err
+				}
+				vars[v.Name] = value
+			}
+		}
+		if v.Required && vars[v.Name] == "" {
+			return nil, fmt.Errorf("template variable %s is required, set it with --var %s=<value>", v.Name, v.Name)
+		}
+	}
+	return vars, nil
+}
+
+// extractTar extracts the regular files and directories of a possibly gzipped
+// tar archive into dir.
+func extractTar(archive, dir string) error {
+	f, err := os.Open(archive)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if !strings.HasSuffix(archive, ".tar") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		// 不允許解壓到目標目錄之外
+		if !filepath.IsLocal(header.Name) {
+			return fmt.Errorf("invalid path in archive: %s", header.Name)
+		}
+		target := filepath.Join(dir, header.Name)
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+			if err != nil {
+				return err
+			}
+			if _, err := io.Copy(out, tr); err != nil {
+				out.Close()
+				return err
+			}
+			if err := out.Close(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// shellCommand returns the platform shell invocation for a post-create command.
+func shellCommand(command string) []string {
+	if runtime.GOOS == "windows" {
+		return []string{"cmd", "/C", command}
+	}
+	return []string{"sh", "-c", command}
+}
@@ -0,0 +1,47 @@
+package create
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// CSSFrameworks lists the CSS frameworks a project can be created with.
+var CSSFrameworks = []string{"none", "pico", "bootstrap"}
+
+// cssImports are the stylesheets imported by src/app.css for each CSS framework.
+var cssImports = map[string]string{
+	"pico":      "https://cdn.jsdelivr.net/npm/@picocss/pico@2/css/pico.min.css",
+	"bootstrap": "https://cdn.jsdelivr.net/npm/bootstrap@5/dist/css/bootstrap.min.css",
+}
+
+// Features lists the optional parts of the scaffolding:
+// gitignore writes a .gitignore, health adds a /healthz route.
+var Features = []string{"gitignore", "health"}
+
+// NormalizeCSS returns the canonical CSS framework name.
+func NormalizeCSS(css string) (string, error) {
+	css = strings.ToLower(strings.TrimSpace(css))
+	if css == "" {
+		return "none", nil
+	}
+	if !slices.Contains(CSSFrameworks, css) {
+		return "", fmt.Errorf("unknown CSS framework %q, must be one of %s", css, strings.Join(CSSFrameworks, ", "))
+	}
+	return css, nil
+}
+
+// ValidateFeatures checks that every feature is one of Features.
+func ValidateFeatures(features []string) error {
+	for _, feature := range features {
+		if !slices.Contains(Features, feature) {
+			return fmt.Errorf("unknown feature %q, must be one of %s", feature, strings.Join(Features, ", "))
+		}
+	}
+	return nil
+}
+
+// CSSImport is the URL of the stylesheet of the CSS framework, or "".
+func (d TemplateData) CSSImport() string {
+	return cssImports[d.CSS]
+}
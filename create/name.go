@@ -0,0 +1,63 @@
+package create
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// projectNamePattern matches names that are safe as a directory and binary name on every platform.
+var projectNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// modulePathElement matches one slash-separated element of a module path.
+var modulePathElement = regexp.MustCompile(`^[A-Za-z0-9_~-][A-Za-z0-9._~-]*$`)
+
+// windowsReservedNames 是 Windows 上不能作為檔名的名稱
+var windowsReservedNames = []string{
+	"con", "prn", "aux", "nul",
+	"com1", "com2", "com3", "com4", "com5", "com6", "com7", "com8", "com9",
+	"lpt1", "lpt2", "lpt3", "lpt4", "lpt5", "lpt6", "lpt7", "lpt8", "lpt9",
+}
+
+// ValidateProjectName checks that name can be used as the project directory and binary name.
+func ValidateProjectName(name string) error {
+	if !projectNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid project name %q: use letters, digits, '.', '-' and '_', starting with a letter or digit", name)
+	}
+	if strings.HasSuffix(name, ".") {
+		return fmt.Errorf("invalid project name %q: must not end with '.'", name)
+	}
+	base := strings.ToLower(strings.SplitN(name, ".", 2)[0])
+	for _, reserved := range windowsReservedNames {
+		if base == reserved {
+			return fmt.Errorf("invalid project name %q: %s is a reserved file name on Windows", name, reserved)
+		}
+	}
+	return nil
+}
+
+// DefaultModulePath returns the module path used when none is given: the
+// project name in lower case.
+func DefaultModulePath(projectName string) string {
+	return strings.ToLower(projectName)
+}
+
+// ValidateModulePath checks that modulePath can be passed to `go mod init` and
+// used in import paths.
+func ValidateModulePath(modulePath string) error {
+	if modulePath == "" {
+		return fmt.Errorf("module path must not be empty")
+	}
+	for _, element := range strings.Split(modulePath, "/") {
+		if element == "" {
+			return fmt.Errorf("invalid module path %q: empty path element", modulePath)
+		}
+		if !modulePathElement.MatchString(element) || strings.HasSuffix(element, ".") {
+			return fmt.Errorf("invalid module path %q: invalid path element %q", modulePath, element)
+		}
+	}
+	if strings.HasPrefix(modulePath, "-") {
+		return fmt.Errorf("invalid module path %q: must not start with '-'", modulePath)
+	}
+	return nil
+}
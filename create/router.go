@@ -0,0 +1,43 @@
+package create
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Routers lists the routers a project can be created with.
+var Routers = []string{"gin", "chi", "echo", "fiber", "nethttp"}
+
+// DefaultRouter is the router used when none is chosen.
+const DefaultRouter = "gin"
+
+// routerTemplate describes the scaffolding of one router. Its templates live in scaffold/routers/<name>.
+type routerTemplate struct {
+	dependency  string // module passed to go get, empty for the standard library
+	constructor string // exported function in router.go returning the router
+}
+
+var routerTemplates = map[string]routerTemplate{
+	"gin":     {dependency: "github.com/gin-gonic/gin", constructor: "GinRouter"},
+	"chi":     {dependency: "github.com/go-chi/chi/v5", constructor: "ChiRouter"},
+	"echo":    {dependency: "github.com/labstack/echo/v4", constructor: "EchoRouter"},
+	"fiber":   {dependency: "github.com/gofiber/fiber/v2", constructor: "FiberApp"},
+	"nethttp": {constructor: "Router"},
+}
+
+// NormalizeRouter returns the canonical router name, accepting aliases like "net/http".
+func NormalizeRouter(router string) (string, error) {
+	router = strings.ToLower(strings.TrimSpace(router))
+	switch router {
+	case "":
+		return DefaultRouter, nil
+	case "net/http", "http", "stdlib", "servemux":
+		return "nethttp", nil
+	}
+	for _, name := range Routers {
+		if name == router {
+			return router, nil
+		}
+	}
+	return "", fmt.Errorf("unknown router %q, must be one of %s", router, strings.Join(Routers, ", "))
+}
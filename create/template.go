@@ -0,0 +1,162 @@
+package create
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"go/format"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+//go:embed scaffold
+var scaffold embed.FS
+
+// TemplateData is the data model the scaffolding templates are rendered with.
+type TemplateData struct {
+	// ModulePath is the Go module path used in import paths.
+	ModulePath string
+	// ProjectName is the project directory and binary name.
+	ProjectName string
+	// Flavor is "golte" or "sveltigo".
+	Flavor string
+	// Router is one of Routers.
+	Router string
+	// Port is the default port the app listens on.
+	Port int
+	// TypeScript writes the Svelte components with lang="ts".
+	TypeScript bool
+	// CSS is one of CSSFrameworks.
+	CSS string
+	// Features are optional parts of the scaffolding that were enabled, see Features.
+	Features []string
+	// Vars are the variables declared in the manifest of a custom template.
+	Vars map[string]string
+}
+
+// IsSveltigo reports whether the project uses Sveltigo.
+func (d TemplateData) IsSveltigo() bool {
+	return d.Flavor == "sveltigo"
+}
+
+// Framework is the Go package name of the flavor.
+func (d TemplateData) Framework() string {
+	if d.IsSveltigo() {
+		return "sveltigo"
+	}
+	return "golte"
+}
+
+// FrameworkName is the display name of the flavor.
+func (d TemplateData) FrameworkName() string {
+	if d.IsSveltigo() {
+		return "Sveltigo"
+	}
+	return "Golte"
+}
+
+// FrameworkImport is the quoted import path of the flavor.
+func (d TemplateData) FrameworkImport() string {
+	if d.IsSveltigo() {
+		return `"github.com/HazelnutParadise/sveltigo"`
+	}
+	return `"github.com/nichady/golte"`
+}
+
+// Middleware is the name of the middleware variable generated in the build package.
+func (d TemplateData) Middleware() string {
+	return d.FrameworkName()
+}
+
+// RouterConstructor is the exported function of the router package returning the router.
+func (d TemplateData) RouterConstructor() string {
+	return routerTemplates[d.Router].constructor
+}
+
+// HasFeature reports whether the feature is enabled.
+func (d TemplateData) HasFeature(feature string) bool {
+	return slices.Contains(d.Features, feature)
+}
+
+// renderTemplate renders the template file name from fsys with data.
+func renderTemplate(fsys fs.FS, name string, data TemplateData) ([]byte, error) {
+	source, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template %s: %v", name, err)
+	}
+	return renderString(name, string(source), data)
+}
+
+// renderString renders the template source called name with data.
+func renderString(name, source string, data TemplateData) ([]byte, error) {
+	tmpl, err := template.New(path.Base(name)).Option("missingkey=error").Parse(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template %s: %v", name, err)
+	}
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, data); err != nil {
+		return nil, fmt.Errorf("failed to render template %s: %v", name, err)
+	}
+	return b.Bytes(), nil
+}
+
+// writeTemplate renders the template name from fsys into destPath. Go files are gofmt'ed.
+func writeTemplate(fsys fs.FS, name, destPath string, data TemplateData) error {
+	content, err := renderTemplate(fsys, name, data)
+	if err != nil {
+		return err
+	}
+	if strings.HasSuffix(destPath, ".go") {
+		return writeGoFile(destPath, string(content))
+	}
+	return os.WriteFile(destPath, content, 0644)
+}
+
+// sourceFiles maps the scaffold templates of the chosen router and flavor to
+// their paths in the project.
+func sourceFiles(data TemplateData) map[string]string {
+	files := map[string]string{
+		"scaffold/main.go.tmpl":                                            "main.go",
+		"scaffold/package.json.tmpl":                                       "package.json",
+		path.Join("scaffold/routers", data.Router, "router.go.tmpl"):       filepath.Join("router", "router.go"),
+		path.Join("scaffold/routers", data.Router, "defineRoutes.go.tmpl"): filepath.Join("router", "defineRoutes.go"),
+	}
+	if data.HasFeature("gitignore") {
+		files["scaffold/gitignore.tmpl"] = ".gitignore"
+	}
+	return files
+}
+
+// writeSourceFiles writes main.go, package.json and the router package for the
+// chosen router and flavor, except the files in skip.
+func writeSourceFiles(projectPath string, data TemplateData, skip map[string]bool) error {
+	// make router directory
+	err := os.MkdirAll(filepath.Join(projectPath, "router"), 0755)
+	if err != nil {
+		return fmt.Errorf("failed to create router directory: %v", err)
+	}
+
+	for name, dest := range sourceFiles(data) {
+		if skip[dest] {
+			continue
+		}
+		if err := writeTemplate(scaffold, name, filepath.Join(projectPath, dest), data); err != nil {
+			return fmt.Errorf("failed to write %s file: %v", dest, err)
+		}
+	}
+	return nil
+}
+
+// writeGoFile formats Go source before writing it, so the generated imports are sorted.
+func writeGoFile(path string, content string) error {
+	formatted, err := format.Source([]byte(content))
+	if err != nil {
+		return fmt.Errorf("failed to format %s: %v", path, err)
+	}
+	return os.WriteFile(path, formatted, 0644)
+}
@@ -0,0 +1,169 @@
+package devserver
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// readyTimeout is how long Resume waits for the app to accept connections.
+const readyTimeout = 10 * time.Second
+
+// holdTimeout is how long a request is held while the app is rebuilding.
+const holdTimeout = 2 * time.Minute
+
+// Server is the development server used by `golte-cli dev`. It listens on a stable
+// port and proxies requests to the running app, holding them while the app is
+// being replaced by a new build. It injects the live reload script into HTML pages and tells
+// connected browsers to reload after the app restarts.
+type Server struct {
+	target   *url.URL
+	proxy    *httputil.ReverseProxy
+	reloader *reloader
+
+	mu   sync.Mutex
+	gate chan struct{} // closed when requests may pass, replaced by Hold
+}
+
+// New returns a dev server that proxies to the app listening at appURL.
+func New(appURL string) (*Server, error) {
+	target, err := url.Parse(appURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid app URL %q: %v", appURL, err)
+	}
+
+	gate := make(chan struct{})
+	close(gate)
+	s := &Server{
+		target:   target,
+		reloader: newReloader(),
+		gate:     gate,
+	}
+	s.proxy = httputil.NewSingleHostReverseProxy(target)
+	director := s.proxy.Director
+	s.proxy.Director = func(r *http.Request) {
+		director(r)
+		// 要求未壓縮的回應，才能注入腳本
+		r.Header.Del("Accept-Encoding")
+	}
+	s.proxy.ModifyResponse = injectReloadScript
+	s.proxy.ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
+		log.Printf("Dev server proxy error: %v", err)
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusBadGateway)
+		fmt.Fprintf(w, "<!DOCTYPE html><html><body><p>The app is not reachable: %v</p>%s</body></html>", err, reloadScript)
+	}
+	return s, nil
+}
+
+// ListenAndServe serves the dev server on addr.
+func (s *Server) ListenAndServe(addr string) error {
+	return http.ListenAndServe(addr, s.Handler())
+}
+
+// Handler returns the handler of the dev server: the live reload endpoint and
+// the proxy to the app.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle(ReloadPath, s.reloader)
+	mux.HandleFunc("/", s.serveProxy)
+	return mux
+}
+
+// Hold makes incoming requests wait until Resume is called.
+func (s *Server) Hold() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	select {
+	case <-s.gate:
+		s.gate = make(chan struct{})
+	default:
+		// already holding
+	}
+}
+
+// Resume lets held requests through. If started is true, it first waits until the
+// new app process accepts connections and afterwards tells browsers to reload.
+func (s *Server) Resume(started bool) {
+	if started {
+		if err := s.waitForApp(readyTimeout); err != nil {
+			log.Printf("App did not become ready: %v", err)
+		}
+	}
+
+	s.mu.Lock()
+	select {
+	case <-s.gate:
+	default:
+		close(s.gate)
+	}
+	s.mu.Unlock()
+
+	if started {
+		s.reloader.broadcast()
+	}
+}
+
+// serveProxy 在替換程序期間暫停請求，直到新的程序可以接受連線
+func (s *Server) serveProxy(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	gate := s.gate
+	s.mu.Unlock()
+
+	select {
+	case <-gate:
+	case <-r.Context().Done():
+		return
+	case <-time.After(holdTimeout):
+		http.Error(w, "timed out waiting for the app to rebuild", http.StatusGatewayTimeout)
+		return
+	}
+	s.proxy.ServeHTTP(w, r)
+}
+
+func (s *Server) waitForApp(timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		conn, err := net.DialTimeout("tcp", s.target.Host, time.Second)
+		if err == nil {
+			conn.Close()
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return err
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+// injectReloadScript 在 HTML 回應的 </body> 前插入 live reload 腳本
+func injectReloadScript(resp *http.Response) error {
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/html") || resp.Header.Get("Content-Encoding") != "" {
+		return nil
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return err
+	}
+
+	if i := bytes.LastIndex(body, []byte("</body>")); i >= 0 {
+		body = append(body[:i], append([]byte(reloadScript), body[i:]...)...)
+	} else {
+		body = append(body, reloadScript...)
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	resp.ContentLength = int64(len(body))
+	resp.Header.Set("Content-Length", strconv.Itoa(len(body)))
+	return nil
+}
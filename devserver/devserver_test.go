@@ -0,0 +1,177 @@
+package devserver
+
+import (
+	"bufio"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestInjectReloadScript(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		encoding    string
+		body        string
+		want        string
+	}{
+		{
+			name:        "before the closing body tag",
+			contentType: "text/html; charset=utf-8",
+			body:        "<html><body><h1>Hi</h1></body></html>",
+			want:        "<html><body><h1>Hi</h1>" + reloadScript + "</body></html>",
+		},
+		{
+			name:        "last closing body tag",
+			contentType: "text/html",
+			body:        "<body><template></body></template></body>",
+			want:        "<body><template></body></template>" + reloadScript + "</body>",
+		},
+		{
+			name:        "without a body tag",
+			contentType: "text/html",
+			body:        "<h1>Hi</h1>",
+			want:        "<h1>Hi</h1>" + reloadScript,
+		},
+		{
+			name:        "not HTML",
+			contentType: "application/json",
+			body:        `{"body":"</body>"}`,
+			want:        `{"body":"</body>"}`,
+		},
+		{
+			name:        "compressed HTML",
+			contentType: "text/html",
+			encoding:    "gzip",
+			body:        "\x1f\x8b compressed </body>",
+			want:        "\x1f\x8b compressed </body>",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{
+				Header:        http.Header{"Content-Type": {tt.contentType}},
+				Body:          io.NopCloser(strings.NewReader(tt.body)),
+				ContentLength: int64(len(tt.body)),
+			}
+			if tt.encoding != "" {
+				resp.Header.Set("Content-Encoding", tt.encoding)
+			}
+			if err := injectReloadScript(resp); err != nil {
+				t.Fatal(err)
+			}
+			got, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("body = %q, want %q", got, tt.want)
+			}
+			if resp.ContentLength != int64(len(tt.want)) {
+				t.Errorf("ContentLength = %d, want %d", resp.ContentLength, len(tt.want))
+			}
+		})
+	}
+}
+
+func TestProxy(t *testing.T) {
+	app := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		// 瀏覽器的 Accept-Encoding 不會傳給應用程式，壓縮的回應由代理解壓縮後再注入腳本
+		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			io.WriteString(w, "<body>"+r.URL.Path+"</body>")
+			return
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		io.WriteString(gz, "<body>"+r.URL.Path+"</body>")
+		gz.Close()
+	}))
+	defer app.Close()
+	server, err := New(app.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dev := httptest.NewServer(server.Handler())
+	defer dev.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, dev.URL+"/about", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	resp, err := http.DefaultTransport.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if want := "<body>/about" + reloadScript + "</body>"; string(body) != want {
+		t.Errorf("body = %q, want %q", body, want)
+	}
+	if got := resp.Header.Get("Content-Encoding"); got != "" {
+		t.Errorf("Content-Encoding = %q, want none", got)
+	}
+	if got := resp.Header.Get("Content-Length"); got != strconv.Itoa(len(body)) {
+		t.Errorf("Content-Length = %s, want %d", got, len(body))
+	}
+}
+
+func TestReload(t *testing.T) {
+	app := httptest.NewServer(http.NotFoundHandler())
+	defer app.Close()
+	server, err := New(app.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dev := httptest.NewServer(server.Handler())
+	defer dev.Close()
+
+	client := &http.Client{Timeout: 5 * time.Second}
+	var streams []*bufio.Reader
+	for range 2 {
+		resp, err := client.Get(dev.URL + ReloadPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		if got := resp.Header.Get("Content-Type"); got != "text/event-stream" {
+			t.Fatalf("Content-Type = %q, want text/event-stream", got)
+		}
+		streams = append(streams, bufio.NewReader(resp.Body))
+	}
+	waitForClients(t, server.reloader, 2)
+
+	// Resume(true) 等到應用程式可以連線後通知所有瀏覽器
+	server.Hold()
+	server.Resume(true)
+	for i, stream := range streams {
+		line, err := stream.ReadString('\n')
+		if err != nil {
+			t.Fatalf("client %d: %v", i, err)
+		}
+		if line != "event: reload\n" {
+			t.Errorf("client %d got %q, want the reload event", i, line)
+		}
+	}
+}
+
+// waitForClients waits until n browsers are connected to rl.
+func waitForClients(t *testing.T, rl *reloader, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		rl.mu.Lock()
+		connected := len(rl.clients)
+		rl.mu.Unlock()
+		if connected == n {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%d client(s) connected, want %d", connected, n)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
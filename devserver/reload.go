@@ -0,0 +1,75 @@
+package devserver
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// ReloadPath is the Server-Sent Events endpoint browsers subscribe to for reloads.
+const ReloadPath = "/__golte-cli/reload"
+
+// reloadScript is injected into every HTML page served through the dev server.
+const reloadScript = `<script>
+(() => {
+	const source = new EventSource("` + ReloadPath + `");
+	source.addEventListener("reload", () => location.reload());
+})();
+</script>
+`
+
+// reloader keeps track of connected browsers and tells them to reload.
+type reloader struct {
+	mu      sync.Mutex
+	clients map[chan struct{}]struct{}
+}
+
+func newReloader() *reloader {
+	return &reloader{clients: make(map[chan struct{}]struct{})}
+}
+
+func (rl *reloader) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	client := make(chan struct{}, 1)
+	rl.mu.Lock()
+	rl.clients[client] = struct{}{}
+	rl.mu.Unlock()
+	defer func() {
+		rl.mu.Lock()
+		delete(rl.clients, client)
+		rl.mu.Unlock()
+	}()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-client:
+			fmt.Fprint(w, "event: reload\ndata: {}\n\n")
+			flusher.Flush()
+		}
+	}
+}
+
+// broadcast 通知所有已連線的瀏覽器重新整理
+func (rl *reloader) broadcast() {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	for client := range rl.clients {
+		select {
+		case client <- struct{}{}:
+		default:
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package generate
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+//go:embed templates
+var templates embed.FS
+
+// Kind is the kind of Svelte file a generator creates.
+type Kind string
+
+const (
+	KindPage      Kind = "page"
+	KindComponent Kind = "component"
+	KindLayout    Kind = "layout"
+)
+
+// dirs are the directories under srcDir the kinds are created in.
+var dirs = map[Kind]string{
+	KindPage:      "pages",
+	KindComponent: "components",
+	KindLayout:    "layouts",
+}
+
+// Options configures Generate.
+type Options struct {
+	ProjectPath string
+	// SrcDir is the absolute path of the srcDir of golte.config.ts.
+	SrcDir string
+	Kind   Kind
+	// Name is the component name, optionally nested like "admin/Users".
+	Name       string
+	TypeScript bool
+
+	// The options below only apply to pages.
+
+	// Route is the URL path of the page. It defaults to the kebab-cased name.
+	Route string
+	// Method is the HTTP method of the route, one of Methods. It defaults to GET.
+	Method string
+	// Layout is the name of a layout under layouts/ the page is rendered in.
+	Layout string
+	// NoRoute only creates the Svelte file.
+	NoRoute bool
+	// Flavor is the project flavor, used when router/defineRoutes.go does not
+	// import Golte or Sveltigo yet.
+	Flavor string
+}
+
+// templateData is the data the Svelte templates are rendered with.
+type templateData struct {
+	// Name is the last element of the component name.
+	Name string
+	// Class is the kebab-cased name, used as CSS class.
+	Class      string
+	TypeScript bool
+}
+
+var nameElement = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_-]*$`)
+
+// Methods are the HTTP methods a generated route can use.
+var Methods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"}
+
+// Generate creates the Svelte file and, for pages, registers a route in
+// router/defineRoutes.go. It returns the files it created or changed.
+func Generate(opts Options) ([]string, error) {
+	dir, ok := dirs[opts.Kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown kind %q", opts.Kind)
+	}
+	name, err := cleanName(opts.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	file := filepath.Join(opts.SrcDir, dir, filepath.FromSlash(name)+".svelte")
+	if _, err := os.Stat(file); err == nil {
+		return nil, fmt.Errorf("%s already exists", file)
+	}
+
+	var r route
+	if opts.Kind == KindPage && !opts.NoRoute {
+		r, err = newRoute(opts, dir, name)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	base := path.Base(name)
+	content, err := render(string(opts.Kind)+".svelte.tmpl", templateData{
+		Name:       base,
+		Class:      kebabCase(base),
+		TypeScript: opts.TypeScript,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(file, content, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %v", file, err)
+	}
+	files := []string{file}
+
+	if r.Component != "" {
+		routesFile := filepath.Join(opts.ProjectPath, RoutesFile)
+		if err := AddRoute(routesFile, r, opts.Flavor); err != nil {
+			// 路由加入失敗時移除剛建立的檔案，讓命令可以重試
+			os.Remove(file)
+			return nil, err
+		}
+		files = append(files, routesFile)
+	}
+	return files, nil
+}
+
+// newRoute returns the route of the page name.
+func newRoute(opts Options, dir, name string) (route, error) {
+	r := route{
+		Method:    strings.ToUpper(opts.Method),
+		Path:      opts.Route,
+		Component: path.Join(dir, name),
+	}
+	if r.Method == "" {
+		r.Method = "GET"
+	}
+	if !slices.Contains(Methods, r.Method) {
+		return route{}, fmt.Errorf("unsupported method %q, use one of %s", opts.Method, strings.Join(Methods, ", "))
+	}
+	if r.Path == "" {
+		r.Path = DefaultRoute(name)
+	}
+	if !strings.HasPrefix(r.Path, "/") {
+		r.Path = "/" + r.Path
+	}
+	if opts.Layout != "" {
+		layout, err := cleanName(opts.Layout)
+		if err != nil {
+			return route{}, fmt.Errorf("invalid layout: %v", err)
+		}
+		layoutFile := filepath.Join(opts.SrcDir, dirs[KindLayout], filepath.FromSlash(layout)+".svelte")
+		if _, err := os.Stat(layoutFile); err != nil {
+			return route{}, fmt.Errorf("layout %s does not exist, create it with `golte-cli generate layout %s`", layoutFile, layout)
+		}
+		r.Layout = path.Join(dirs[KindLayout], layout)
+	}
+	return r, nil
+}
+
+// cleanName validates a component name like "admin/Users" and strips a .svelte suffix.
+func cleanName(name string) (string, error) {
+	name = strings.TrimSuffix(filepath.ToSlash(name), ".svelte")
+	if name == "" {
+		return "", fmt.Errorf("name must not be empty")
+	}
+	for _, element := range strings.Split(name, "/") {
+		if !nameElement.MatchString(element) {
+			return "", fmt.Errorf("invalid name %q: each path element must start with a letter and contain only letters, digits, '-' and '_'", name)
+		}
+	}
+	return name, nil
+}
+
+// DefaultRoute returns the URL path of the page name, e.g. /admin/user-list
+// for admin/UserList.
+func DefaultRoute(name string) string {
+	elements := strings.Split(name, "/")
+	for i, element := range elements {
+		elements[i] = kebabCase(element)
+	}
+	return "/" + strings.Join(elements, "/")
+}
+
+// kebabCase converts UserList and user_list to user-list.
+func kebabCase(s string) string {
+	var b strings.Builder
+	runes := []rune(s)
+	for i, r := range runes {
+		switch {
+		case r == '_' || r == '-':
+			b.WriteRune('-')
+		case unicode.IsUpper(r):
+			if i > 0 && runes[i-1] != '_' && runes[i-1] != '-' &&
+				(unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+				b.WriteRune('-')
+			}
+			b.WriteRune(unicode.ToLower(r))
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// render renders the Svelte template name.
+func render(name string, data templateData) ([]byte, error) {
+	tmpl, err := template.ParseFS(templates, path.Join("templates", name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template %s: %v", name, err)
+	}
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, data); err != nil {
+		return nil, fmt.Errorf("failed to render template %s: %v", name, err)
+	}
+	return b.Bytes(), nil
+}
+
+// DetectTypeScript reports whether the Svelte files under srcDir use TypeScript.
+func DetectTypeScript(srcDir string) bool {
+	found := false
+	filepath.WalkDir(srcDir, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if !d.IsDir() && strings.HasSuffix(p, ".svelte") {
+			content, err := os.ReadFile(p)
+			if err == nil && bytes.Contains(content, []byte(`lang="ts"`)) {
+				found = true
+				return filepath.SkipAll
+			}
+		}
+		return nil
+	})
+	return found
+}
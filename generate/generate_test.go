@@ -0,0 +1,56 @@
+package generate
+
+import (
+	"testing"
+)
+
+func TestNewRoute(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    Options
+		page    string
+		want    route
+		wantErr bool
+	}{
+		{
+			name: "defaults",
+			page: "admin/UserList",
+			want: route{Method: "GET", Path: "/admin/user-list", Component: "pages/admin/UserList"},
+		},
+		{
+			name: "method and path",
+			opts: Options{Method: "post", Route: "contact-us"},
+			page: "Contact",
+			want: route{Method: "POST", Path: "/contact-us", Component: "pages/Contact"},
+		},
+		{
+			name:    "unsupported method",
+			opts:    Options{Method: "TRACE"},
+			page:    "Contact",
+			wantErr: true,
+		},
+		{
+			name:    "invalid method",
+			opts:    Options{Method: "GET /x"},
+			page:    "Contact",
+			wantErr: true,
+		},
+		{
+			name:    "missing layout",
+			opts:    Options{Layout: "Main", SrcDir: "testdata/none"},
+			page:    "Contact",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newRoute(tt.opts, dirs[KindPage], tt.page)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("newRoute() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("newRoute() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package generate
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/TimLai666/golte-cli/analysis"
+)
+
+// GenerateProps writes the TypeScript declaration of the props of every page
+// the Go code renders next to its Svelte file, see analysis.PropsFile. Files
+// are only rewritten when they change; pages without a Svelte file are left
+// out. It returns the files it wrote and the pages skipped because their props
+// cannot be determined.
+func GenerateProps(projectPath, srcDir string) (written, skipped []string, err error) {
+	project, err := analysis.Load(projectPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, page := range project.PageProps() {
+		if !analysis.ComponentExists(srcDir, page.Component) {
+			// 缺少的頁面由 check 回報
+			continue
+		}
+		if page.Unknown && len(page.Props) == 0 {
+			skipped = append(skipped, page.Component)
+			continue
+		}
+		file := analysis.PropsFile(srcDir, page.Component)
+		content := []byte(page.TypeScript())
+		if existing, err := os.ReadFile(file); err == nil && bytes.Equal(existing, content) {
+			continue
+		}
+		if err := os.WriteFile(file, content, 0644); err != nil {
+			return written, skipped, fmt.Errorf("failed to write %s: %v", file, err)
+		}
+		written = append(written, file)
+	}
+	return written, skipped, nil
+}
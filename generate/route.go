@@ -0,0 +1,342 @@
+package generate
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+// RoutesFile is the file, relative to the project, routes are added to.
+const RoutesFile = "router/defineRoutes.go"
+
+// routesFunc is the function in RoutesFile that registers the routes.
+const routesFunc = "defineRoutes"
+
+// Import paths of the flavors.
+const (
+	golteImport    = "github.com/nichady/golte"
+	sveltigoImport = "github.com/HazelnutParadise/sveltigo"
+)
+
+// route is a page route added to RoutesFile.
+type route struct {
+	Method string
+	Path   string
+	// Component is the page component like "pages/About".
+	Component string
+	// Layout is the layout component like "layouts/Main", or "".
+	Layout string
+}
+
+// routerImports maps the import path of each supported router to its style.
+var routerImports = map[string]string{
+	"github.com/gin-gonic/gin":    "gin",
+	"github.com/go-chi/chi":       "chi",
+	"github.com/go-chi/chi/v5":    "chi",
+	"github.com/labstack/echo/v4": "echo",
+	"github.com/gofiber/fiber/v2": "fiber",
+	"net/http":                    "nethttp",
+}
+
+// routeFile is a parsed RoutesFile.
+type routeFile struct {
+	fset *token.FileSet
+	file *ast.File
+	fn   *ast.FuncDecl
+	// router is the name of the router parameter of fn, style the router it is
+	// and routerPkg the local name of its package.
+	router    string
+	style     string
+	routerPkg string
+	// framework is the local name of the Golte or Sveltigo import, or "" if missing.
+	framework string
+}
+
+// AddRoute registers r in defineRoutes in the file, in the style of the router
+// the function receives. The statement is built with go/ast, appended to the
+// function body and the file is printed with go/format. flavor selects the
+// package imported if the file imports neither Golte nor Sveltigo.
+func AddRoute(file string, r route, flavor string) error {
+	source, err := os.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %v", file, err)
+	}
+	rf, err := parseRouteFile(file, source)
+	if err != nil {
+		return err
+	}
+	if rf.hasRoute(r.Method, r.Path) {
+		return fmt.Errorf("%s already registers %s %s", file, r.Method, r.Path)
+	}
+
+	framework := rf.framework
+	if framework == "" {
+		framework = path.Base(golteImport)
+		if flavor == "sveltigo" {
+			framework = path.Base(sveltigoImport)
+		}
+	}
+	stmt, err := routeStatement(rf.style, rf.router, rf.routerPkg, framework, r)
+	if err != nil {
+		return err
+	}
+	// go/printer 依位元組位置穿插註解，新語句沒有原始位置，
+	// 因此在結尾的 } 前預留空白，讓新語句的位置不會越過後面的註解
+	var printed bytes.Buffer
+	if err := format.Node(&printed, token.NewFileSet(), stmt); err != nil {
+		return fmt.Errorf("failed to generate route: %v", err)
+	}
+	rbrace := rf.fset.Position(rf.fn.Body.Rbrace).Offset
+	padded := slices.Concat(source[:rbrace], bytes.Repeat([]byte(" "), 2*printed.Len()+64), source[rbrace:])
+	if rf, err = parseRouteFile(file, padded); err != nil {
+		return err
+	}
+
+	if rf.framework == "" {
+		importPath := golteImport
+		if flavor == "sveltigo" {
+			importPath = sveltigoImport
+		}
+		astutil.AddImport(rf.fset, rf.file, importPath)
+	}
+	if rf.style != "gin" && rf.style != "echo" {
+		astutil.AddImport(rf.fset, rf.file, "net/http")
+	}
+	rf.separate(stmt, rbrace)
+	rf.fn.Body.List = append(rf.fn.Body.List, stmt)
+
+	var b bytes.Buffer
+	if err := format.Node(&b, rf.fset, rf.file); err != nil {
+		return fmt.Errorf("failed to format %s: %v", file, err)
+	}
+	// 確認結果仍可解析且包含新的路由
+	check, err := parseRouteFile(file, b.Bytes())
+	if err != nil || !check.hasRoute(r.Method, r.Path) {
+		return fmt.Errorf("failed to add the route to %s", file)
+	}
+	return os.WriteFile(file, b.Bytes(), 0644)
+}
+
+// separate gives stmt positions so it is printed after a blank line following
+// the other statements, like the routes in the templates, and its handlers span
+// several lines. The positions are at offset, in files of the fileset whose
+// lines are laid out for that, so the comments before offset are printed before stmt.
+func (rf *routeFile) separate(stmt *ast.ExprStmt, offset int) {
+	line := rf.fset.Position(rf.fn.Body.Rbrace).Line
+	if len(rf.fn.Body.List) > 0 {
+		line++
+	}
+	call := stmt.X.(*ast.CallExpr)
+	call.Fun.(*ast.SelectorExpr).X.(*ast.Ident).NamePos = rf.linePos(offset, line)
+	ast.Inspect(call, func(n ast.Node) bool {
+		if lit, ok := n.(*ast.FuncLit); ok {
+			lit.Body.Lbrace = rf.linePos(offset, line)
+			lit.Body.Rbrace = rf.linePos(offset, line+1)
+			return false
+		}
+		return true
+	})
+}
+
+// linePos adds a file to the fileset and returns the position at offset in
+// it, which is on line.
+func (rf *routeFile) linePos(offset, line int) token.Pos {
+	offset = max(offset, line)
+	lines := make([]int, line)
+	for i := range line - 1 {
+		lines[i] = i
+	}
+	lines[line-1] = offset
+	f := rf.fset.AddFile("", -1, offset+1)
+	f.SetLines(lines)
+	return f.Pos(offset)
+}
+
+// parseRouteFile parses source and finds defineRoutes and its router.
+func parseRouteFile(name string, source []byte) (*routeFile, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, name, source, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", name, err)
+	}
+	rf := &routeFile{fset: fset, file: file}
+	for _, decl := range file.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv == nil && fn.Name.Name == routesFunc && fn.Body != nil {
+			rf.fn = fn
+		}
+	}
+	if rf.fn == nil {
+		return nil, fmt.Errorf("%s has no %s function", name, routesFunc)
+	}
+	params := rf.fn.Type.Params.List
+	if len(params) == 0 || len(params[0].Names) == 0 {
+		return nil, fmt.Errorf("%s in %s has no router parameter", routesFunc, name)
+	}
+	rf.router = params[0].Names[0].Name
+	rf.style, rf.routerPkg = rf.routerStyle(params[0].Type)
+	if rf.style == "" {
+		return nil, fmt.Errorf("unsupported router type of %s in %s", routesFunc, name)
+	}
+	for _, spec := range file.Imports {
+		importPath, _ := strconv.Unquote(spec.Path.Value)
+		if importPath == golteImport || importPath == sveltigoImport {
+			rf.framework = importName(spec)
+		}
+	}
+	return rf, nil
+}
+
+// importName returns the name the import is referred to by in the file.
+func importName(spec *ast.ImportSpec) string {
+	if spec.Name != nil {
+		return spec.Name.Name
+	}
+	importPath, _ := strconv.Unquote(spec.Path.Value)
+	name := path.Base(importPath)
+	if len(name) > 1 && name[0] == 'v' && strings.Trim(name[1:], "0123456789") == "" {
+		name = path.Base(path.Dir(importPath))
+	}
+	return name
+}
+
+// routerStyle returns the style of the router type like *gin.Engine or
+// chi.Router and the name its package is imported as.
+func (rf *routeFile) routerStyle(typ ast.Expr) (style, pkgName string) {
+	if star, ok := typ.(*ast.StarExpr); ok {
+		typ = star.X
+	}
+	sel, ok := typ.(*ast.SelectorExpr)
+	if !ok {
+		return "", ""
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return "", ""
+	}
+	for _, spec := range rf.file.Imports {
+		if importName(spec) == pkg.Name {
+			importPath, _ := strconv.Unquote(spec.Path.Value)
+			return routerImports[importPath], pkg.Name
+		}
+	}
+	return "", ""
+}
+
+// hasRoute reports whether defineRoutes registers method and routePath on the router.
+func (rf *routeFile) hasRoute(method, routePath string) bool {
+	found := false
+	ast.Inspect(rf.fn.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || found || len(call.Args) == 0 {
+			return !found
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		if x, ok := sel.X.(*ast.Ident); !ok || x.Name != rf.router {
+			return true
+		}
+		lit, ok := call.Args[0].(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			return true
+		}
+		value, _ := strconv.Unquote(lit.Value)
+		if rf.style == "nethttp" {
+			m, p, ok := strings.Cut(value, " ")
+			found = ok && strings.EqualFold(m, method) && samePath(p, routePath)
+		} else {
+			found = strings.EqualFold(sel.Sel.Name, method) && samePath(value, routePath)
+		}
+		return !found
+	})
+	return found
+}
+
+// samePath compares paths, treating the ServeMux pattern "/{$}" as "/".
+func samePath(a, b string) bool {
+	return strings.TrimSuffix(a, "{$}") == strings.TrimSuffix(b, "{$}")
+}
+
+// routeStatement returns the statement registering r on router in style.
+// routerPkg is the local name of the router package, used for the context types.
+func routeStatement(style, router, routerPkg, framework string, r route) (*ast.ExprStmt, error) {
+	// layout 在 RenderPage 之前加入，頁面會在 layout 的 <slot /> 中渲染
+	render := func(writer, request ast.Expr) []ast.Stmt {
+		var stmts []ast.Stmt
+		if r.Layout != "" {
+			stmts = append(stmts, &ast.ExprStmt{X: call(selector(framework, "AddLayout"), request, stringLit(r.Layout), ast.NewIdent("nil"))})
+		}
+		props := &ast.CompositeLit{Type: &ast.MapType{Key: ast.NewIdent("string"), Value: ast.NewIdent("any")}}
+		return append(stmts, &ast.ExprStmt{X: call(selector(framework, "RenderPage"), writer, request, stringLit(r.Component), props)})
+	}
+	// httpHandler 是 func(w http.ResponseWriter, r *http.Request) { ... }
+	httpHandler := func(request string) *ast.FuncLit {
+		return handler(render(ast.NewIdent("w"), ast.NewIdent(request)),
+			param("w", selector("http", "ResponseWriter")),
+			param(request, &ast.StarExpr{X: selector("http", "Request")}))
+	}
+	title := strings.ToUpper(r.Method[:1]) + strings.ToLower(r.Method[1:])
+
+	var register *ast.CallExpr
+	switch style {
+	case "gin":
+		register = call(selector(router, r.Method), stringLit(r.Path),
+			handler(render(selector("ctx", "Writer"), selector("ctx", "Request")),
+				param("ctx", &ast.StarExpr{X: selector(routerPkg, "Context")})))
+	case "chi":
+		register = call(selector(router, title), stringLit(r.Path), httpHandler("req"))
+	case "echo":
+		body := render(call(selector("c", "Response")), call(selector("c", "Request")))
+		body = append(body, &ast.ReturnStmt{Results: []ast.Expr{ast.NewIdent("nil")}})
+		lit := handler(body, param("c", selector(routerPkg, "Context")))
+		lit.Type.Results = &ast.FieldList{List: []*ast.Field{{Type: ast.NewIdent("error")}}}
+		register = call(selector(router, r.Method), stringLit(r.Path), lit)
+	case "fiber":
+		register = call(selector(router, title), stringLit(r.Path), call(ast.NewIdent("page"), httpHandler("r")))
+	case "nethttp":
+		pattern := r.Path
+		if pattern == "/" {
+			pattern = "/{$}"
+		}
+		register = call(selector(router, "HandleFunc"), stringLit(r.Method+" "+pattern), httpHandler("r"))
+	default:
+		return nil, fmt.Errorf("unsupported router %s", style)
+	}
+	return &ast.ExprStmt{X: register}, nil
+}
+
+// selector returns the expression x.name.
+func selector(x, name string) *ast.SelectorExpr {
+	return &ast.SelectorExpr{X: ast.NewIdent(x), Sel: ast.NewIdent(name)}
+}
+
+func call(fun ast.Expr, args ...ast.Expr) *ast.CallExpr {
+	return &ast.CallExpr{Fun: fun, Args: args}
+}
+
+func stringLit(s string) *ast.BasicLit {
+	return &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(s)}
+}
+
+func param(name string, typ ast.Expr) *ast.Field {
+	return &ast.Field{Names: []*ast.Ident{ast.NewIdent(name)}, Type: typ}
+}
+
+// handler returns a function literal with params and body.
+func handler(body []ast.Stmt, params ...*ast.Field) *ast.FuncLit {
+	return &ast.FuncLit{
+		Type: &ast.FuncType{Params: &ast.FieldList{List: params}},
+		Body: &ast.BlockStmt{List: body},
+	}
+}
@@ -0,0 +1,250 @@
+package generate
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAddRoute(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		route  route
+		flavor string
+		want   string
+		// err is a substring of the expected error.
+		err string
+	}{
+		{
+			name: "gin",
+			source: `package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nichady/golte"
+)
+
+func defineRoutes(r *gin.Engine) {
+	r.GET("/", func(ctx *gin.Context) {
+		golte.RenderPage(ctx.Writer, ctx.Request, "pages/App", map[string]any{})
+	})
+}
+`,
+			route: route{Method: "GET", Path: "/about", Component: "pages/About", Layout: "layouts/Main"},
+			want: `package router
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/nichady/golte"
+)
+
+func defineRoutes(r *gin.Engine) {
+	r.GET("/", func(ctx *gin.Context) {
+		golte.RenderPage(ctx.Writer, ctx.Request, "pages/App", map[string]any{})
+	})
+
+	r.GET("/about", func(ctx *gin.Context) {
+		golte.AddLayout(ctx.Request, "layouts/Main", nil)
+		golte.RenderPage(ctx.Writer, ctx.Request, "pages/About", map[string]any{})
+	})
+}
+`,
+		},
+		{
+			name: "chi without framework import",
+			source: `package router
+
+import "github.com/go-chi/chi/v5"
+
+// defineRoutes registers the routes.
+func defineRoutes(r chi.Router) {
+	r.Get("/health", health) // health check
+
+	// more routes here
+}
+
+// health reports that the app is up.
+func health(w http.ResponseWriter, r *http.Request) {}
+`,
+			route:  route{Method: "POST", Path: "/contact", Component: "pages/Contact"},
+			flavor: "sveltigo",
+			want: `package router
+
+import (
+	"github.com/HazelnutParadise/sveltigo"
+	"github.com/go-chi/chi/v5"
+	"net/http"
+)
+
+// defineRoutes registers the routes.
+func defineRoutes(r chi.Router) {
+	r.Get("/health", health) // health check
+
+	// more routes here
+
+	r.Post("/contact", func(w http.ResponseWriter, req *http.Request) {
+		sveltigo.RenderPage(w, req, "pages/Contact", map[string]any{})
+	})
+}
+
+// health reports that the app is up.
+func health(w http.ResponseWriter, r *http.Request) {}
+`,
+		},
+		{
+			name: "echo",
+			source: `package router
+
+import (
+	"github.com/HazelnutParadise/sveltigo"
+	"github.com/labstack/echo/v4"
+)
+
+func defineRoutes(e *echo.Echo) {
+}
+`,
+			route: route{Method: "DELETE", Path: "/items", Component: "pages/Items"},
+			want: `package router
+
+import (
+	"github.com/HazelnutParadise/sveltigo"
+	"github.com/labstack/echo/v4"
+)
+
+func defineRoutes(e *echo.Echo) {
+	e.DELETE("/items", func(c echo.Context) error {
+		sveltigo.RenderPage(c.Response(), c.Request(), "pages/Items", map[string]any{})
+		return nil
+	})
+}
+`,
+		},
+		{
+			name: "fiber",
+			source: `package router
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/nichady/golte"
+)
+
+func defineRoutes(app *fiber.App) {
+	app.Get("/healthz", func(c *fiber.Ctx) error {
+		return c.SendString("ok")
+	})
+}
+`,
+			route: route{Method: "PUT", Path: "/settings", Component: "pages/Settings"},
+			want: `package router
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/nichady/golte"
+)
+
+func defineRoutes(app *fiber.App) {
+	app.Get("/healthz", func(c *fiber.Ctx) error {
+		return c.SendString("ok")
+	})
+
+	app.Put("/settings", page(func(w http.ResponseWriter, r *http.Request) {
+		golte.RenderPage(w, r, "pages/Settings", map[string]any{})
+	}))
+}
+`,
+		},
+		{
+			name: "net/http root",
+			source: `package router
+
+import (
+	"net/http"
+
+	g "github.com/nichady/golte"
+)
+
+func defineRoutes(mux *http.ServeMux) {}
+`,
+			route: route{Method: "GET", Path: "/", Component: "pages/Home"},
+			want: `package router
+
+import (
+	"net/http"
+
+	g "github.com/nichady/golte"
+)
+
+func defineRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		g.RenderPage(w, r, "pages/Home", map[string]any{})
+	})
+}
+`,
+		},
+		{
+			name: "existing route",
+			source: `package router
+
+import "net/http"
+
+func defineRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /{$}", nil)
+}
+`,
+			route: route{Method: "GET", Path: "/", Component: "pages/Home"},
+			err:   "already registers GET /",
+		},
+		{
+			name: "unsupported router",
+			source: `package router
+
+import "github.com/gorilla/mux"
+
+func defineRoutes(r *mux.Router) {}
+`,
+			route: route{Method: "GET", Path: "/", Component: "pages/Home"},
+			err:   "unsupported router type",
+		},
+		{
+			name:   "missing function",
+			source: "package router\n",
+			route:  route{Method: "GET", Path: "/", Component: "pages/Home"},
+			err:    "has no defineRoutes function",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "defineRoutes.go")
+			if err := os.WriteFile(file, []byte(tt.source), 0644); err != nil {
+				t.Fatal(err)
+			}
+			flavor := tt.flavor
+			if flavor == "" {
+				flavor = "golte"
+			}
+			err := AddRoute(file, tt.route, flavor)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("AddRoute() error = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("AddRoute() wrote\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
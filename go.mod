@@ -2,12 +2,20 @@ module github.com/TimLai666/golte-cli
 
 go 1.22.7
 
-require github.com/spf13/cobra v1.8.1
+require (
+	github.com/BurntSushi/toml v1.4.0
+	github.com/spf13/cobra v1.8.1
+	golang.org/x/tools v0.30.0
+)
 
-require golang.org/x/sys v0.13.0 // indirect
+require (
+	golang.org/x/mod v0.23.0 // indirect
+	golang.org/x/sync v0.11.0 // indirect
+	golang.org/x/sys v0.30.0 // indirect
+)
 
 require (
 	github.com/fsnotify/fsnotify v1.8.0
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
-	github.com/spf13/pflag v1.0.5 // indirect
+	github.com/spf13/pflag v1.0.5
 )
@@ -4,13 +4,15 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"runtime"
 	"strings"
 )
 
 func InstallBun() (bunPath string, err error) {
 	// 檢查是否已安裝
-	if path := getBunPath(); path != "" {
+	if path := findBun(nil); path != "" {
+		fmt.Println("Bun already installed at", path)
 		return path, nil
 	}
 
@@ -18,51 +20,90 @@ func InstallBun() (bunPath string, err error) {
 	switch runtime.GOOS {
 	case "windows":
 		cmd = exec.Command("powershell", "-c", "irm bun.sh/install.ps1 | iex")
-		err = cmd.Run()
-		if err != nil {
-			return "", fmt.Errorf("failed to install bun: %v", err)
-		}
-		return getBunPath(), nil
 	case "linux", "darwin":
-		path := findBunInUnix()
-		if path != "" {
-			fmt.Println("Bun already installed at", path)
-			return path, nil
-		}
 		cmd = exec.Command("bash", "-c", "curl -fsSL https://bun.sh/install | bash")
-		err = cmd.Run()
-		if err != nil {
-			return "", fmt.Errorf("failed to install bun: %v", err)
-		}
-		return findBunInUnix(), nil
 	default:
 		return "", fmt.Errorf("unsupported operating system: %s", runtime.GOOS)
 	}
+	err = cmd.Run()
+	if err != nil {
+		return "", fmt.Errorf("failed to install bun: %v", err)
+	}
+	if path := findBun(nil); path != "" {
+		return path, nil
+	}
+	return "", fmt.Errorf("bun was installed but could not be found in %s", strings.Join(bunCandidates(nil), ", "))
 }
 
-func getBunPath() string {
-	var cmd *exec.Cmd
-	if runtime.GOOS == "windows" {
-		cmd = exec.Command("where", "bun.exe")
-	} else {
-		cmd = exec.Command("which", "bun")
+// findBun 依序檢查 bunCandidates 中的路徑，回傳第一個能執行 `bun --version` 的執行檔
+func findBun(searchPaths []string) string {
+	return findBunVersion(searchPaths, "")
+}
+
+// findBunVersion 與 findBun 相同，但優先回傳版本符合 version 的執行檔（包含 install-bun 下載的版本）
+func findBunVersion(searchPaths []string, version string) string {
+	candidates := bunCandidates(searchPaths)
+	if version != "" {
+		if path, err := releaseBunPath(version); err == nil {
+			candidates = append(candidates, path)
+		}
 	}
-	output, err := cmd.Output()
-	if err != nil {
-		return ""
+
+	var first string
+	for _, path := range candidates {
+		got, err := BunVersion(path)
+		if err != nil {
+			continue
+		}
+		if version == "" || normalizeVersion(got) == normalizeVersion(version) {
+			return path
+		}
+		if first == "" {
+			first = path
+		}
 	}
-	return strings.TrimSpace(string(output))
+	return first
 }
 
-func findBunInUnix() (path string) {
-	homeDir := os.Getenv("HOME")
-	if homeDir == "" {
-		return ""
+// bunCandidates 回傳 Bun 可能的位置：PATH、$BUN_INSTALL/bin、~/.bun/bin，最後是設定的路徑
+func bunCandidates(searchPaths []string) []string {
+	execName := bunExecName()
+	var candidates []string
+	if path, err := exec.LookPath(execName); err == nil {
+		candidates = append(candidates, path)
+	}
+	if bunInstall := os.Getenv("BUN_INSTALL"); bunInstall != "" {
+		candidates = append(candidates, filepath.Join(bunInstall, "bin", execName))
+	}
+	if homeDir, err := os.UserHomeDir(); err == nil {
+		candidates = append(candidates, filepath.Join(homeDir, ".bun", "bin", execName))
+	}
+	for _, path := range searchPaths {
+		// 設定的路徑可以是執行檔本身或其所在目錄
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			path = filepath.Join(path, execName)
+		}
+		candidates = append(candidates, path)
 	}
-	cmd := exec.Command("find", homeDir, "-name", "bun", "-type", "f")
-	b, err := cmd.Output()
+	return candidates
+}
+
+// BunVersion runs `bun --version` and returns the reported version.
+func BunVersion(bunPath string) (string, error) {
+	output, err := exec.Command(bunPath, "--version").Output()
 	if err != nil {
-		return ""
+		return "", fmt.Errorf("failed to run %s --version: %v", bunPath, err)
+	}
+	version := strings.TrimSpace(string(output))
+	if version == "" {
+		return "", fmt.Errorf("%s --version reported no version", bunPath)
+	}
+	return version, nil
+}
+
+func bunExecName() string {
+	if runtime.GOOS == "windows" {
+		return "bun.exe"
 	}
-	return strings.TrimSpace(string(b))
+	return "bun"
 }
@@ -0,0 +1,210 @@
+package install
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// MirrorEnvVar is the environment variable that overrides the Bun release download location.
+const MirrorEnvVar = "GOLTE_BUN_MIRROR"
+
+// DefaultMirror is where Bun release archives are downloaded from by default.
+const DefaultMirror = "https://github.com/oven-sh/bun/releases/download"
+
+// InstallBunRelease downloads the release archive of the given Bun version, verifies it
+// and extracts it into the golte-cli cache. mirror uses the same layout as the GitHub
+// releases (<mirror>/bun-v<version>/<asset>) and may be an http(s) URL, a file:// URL
+// or a local directory. The archive is verified against the checksum pinned for its
+// asset, like "bun-linux-x64", in checksums or, if none is pinned, against the
+// SHASUMS256.txt of the official GitHub release, never against one from the mirror.
+func InstallBunRelease(version, mirror string, checksums map[string]string) (string, error) {
+	version = normalizeVersion(version)
+	if mirror == "" {
+		mirror = os.Getenv(MirrorEnvVar)
+	}
+	if mirror == "" {
+		mirror = DefaultMirror
+	}
+
+	destPath, err := releaseBunPath(version)
+	if err != nil {
+		return "", err
+	}
+	if _, err := BunVersion(destPath); err == nil {
+		fmt.Printf("Bun %s already installed at %s\n", version, destPath)
+		return destPath, nil
+	}
+
+	asset, err := releaseAsset()
+	if err != nil {
+		return "", err
+	}
+	base := strings.TrimSuffix(mirror, "/") + "/bun-v" + version + "/"
+
+	fmt.Printf("Downloading %s%s...\n", base, asset+".zip")
+	archive, err := fetch(base + asset + ".zip")
+	if err != nil {
+		return "", err
+	}
+	if pinned, ok := checksums[asset]; ok {
+		err = verifyPinnedSHA256(archive, pinned, asset+".zip")
+	} else {
+		var sums []byte
+		sums, err = fetch(DefaultMirror + "/bun-v" + version + "/SHASUMS256.txt")
+		if err != nil {
+			return "", fmt.Errorf("%v\nThe checksums are always taken from the official release; to install offline, pin the checksum of %s.zip with bunSha256 in golte-cli.toml", err, asset)
+		}
+		err = verifySHA256(archive, sums, asset+".zip")
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if err := extractBun(archive, destPath); err != nil {
+		return "", err
+	}
+	if err := CheckBunVersion(destPath, version); err != nil {
+		return "", err
+	}
+	return destPath, nil
+}
+
+// releaseBunPath 回傳指定版本安裝後的執行檔位置
+func releaseBunPath(version string) (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate cache directory: %v", err)
+	}
+	return filepath.Join(cacheDir, "golte-cli", "bun", normalizeVersion(version), bunExecName()), nil
+}
+
+// releaseAsset 回傳目前平台的 release 檔名（不含 .zip）
+func releaseAsset() (string, error) {
+	var arch string
+	switch runtime.GOARCH {
+	case "amd64":
+		arch = "x64"
+	case "arm64":
+		arch = "aarch64"
+	default:
+		return "", fmt.Errorf("unsupported architecture: %s", runtime.GOARCH)
+	}
+	switch runtime.GOOS {
+	case "linux", "darwin", "windows":
+		return fmt.Sprintf("bun-%s-%s", runtime.GOOS, arch), nil
+	default:
+		return "", fmt.Errorf("unsupported operating system: %s", runtime.GOOS)
+	}
+}
+
+func fetch(url string) ([]byte, error) {
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		data, err := os.ReadFile(filepath.FromSlash(strings.TrimPrefix(url, "file://")))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %v", url, err)
+		}
+		return data, nil
+	}
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %v", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download %s: %s", url, resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %v", url, err)
+	}
+	return data, nil
+}
+
+func verifySHA256(data, sums []byte, fileName string) error {
+	sum := sha256.Sum256(data)
+	got := hex.EncodeToString(sum[:])
+
+	scanner := bufio.NewScanner(bytes.NewReader(sums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 || strings.TrimPrefix(fields[1], "*") != fileName {
+			continue
+		}
+		if !strings.EqualFold(fields[0], got) {
+			return fmt.Errorf("checksum mismatch for %s: expected %s, got %s", fileName, fields[0], got)
+		}
+		return nil
+	}
+	return fmt.Errorf("no checksum for %s in SHASUMS256.txt", fileName)
+}
+
+// verifyPinnedSHA256 compares the SHA-256 of data with the pinned hex checksum.
+func verifyPinnedSHA256(data []byte, pinned, fileName string) error {
+	sum := sha256.Sum256(data)
+	if got := hex.EncodeToString(sum[:]); !strings.EqualFold(pinned, got) {
+		return fmt.Errorf("checksum mismatch for %s: pinned %s, got %s", fileName, pinned, got)
+	}
+	return nil
+}
+
+// extractBun 將 archive 中的 bun 寫入同一目錄的暫存檔，完整寫入並 fsync 後才 rename 成 destPath，
+// 中斷或失敗時不會留下不完整的 bun
+func extractBun(archive []byte, destPath string) error {
+	reader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return fmt.Errorf("failed to open bun archive: %v", err)
+	}
+	for _, file := range reader.File {
+		if file.FileInfo().IsDir() || filepath.Base(file.Name) != bunExecName() {
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(destPath), 0755); err != nil {
+			return fmt.Errorf("failed to create %s: %v", filepath.Dir(destPath), err)
+		}
+		src, err := file.Open()
+		if err != nil {
+			return fmt.Errorf("failed to extract %s: %v", file.Name, err)
+		}
+		defer src.Close()
+		tmp, err := os.CreateTemp(filepath.Dir(destPath), "."+filepath.Base(destPath)+".tmp-*")
+		if err != nil {
+			return fmt.Errorf("failed to write %s: %v", destPath, err)
+		}
+		if err := writeExecutable(tmp, src); err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+			return fmt.Errorf("failed to write %s: %v", destPath, err)
+		}
+		if err := tmp.Close(); err != nil {
+			os.Remove(tmp.Name())
+			return fmt.Errorf("failed to write %s: %v", destPath, err)
+		}
+		if err := os.Rename(tmp.Name(), destPath); err != nil {
+			os.Remove(tmp.Name())
+			return fmt.Errorf("failed to write %s: %v", destPath, err)
+		}
+		return nil
+	}
+	return fmt.Errorf("bun archive does not contain %s", bunExecName())
+}
+
+// writeExecutable copies src to dst, makes it executable and flushes it to disk.
+func writeExecutable(dst *os.File, src io.Reader) error {
+	if _, err := io.Copy(dst, src); err != nil {
+		return err
+	}
+	if err := dst.Chmod(0755); err != nil {
+		return err
+	}
+	return dst.Sync()
+}
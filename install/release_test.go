@@ -0,0 +1,74 @@
+package install
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExtractBun(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "replaces the existing bun",
+			files: map[string]string{"bun-linux-x64/" + bunExecName(): "new bun"},
+			want:  "new bun",
+		},
+		{
+			name:    "archive without bun keeps the existing bun",
+			files:   map[string]string{"bun-linux-x64/README.md": "readme"},
+			want:    "old bun",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			dest := filepath.Join(dir, bunExecName())
+			if err := os.WriteFile(dest, []byte("old bun"), 0755); err != nil {
+				t.Fatal(err)
+			}
+			err := extractBun(zipArchive(t, tt.files), dest)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("extractBun() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			got, err := os.ReadFile(dest)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("bun = %q, want %q", got, tt.want)
+			}
+			// 暫存檔不會留在目錄中
+			if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+				t.Errorf("directory has %d entries, want only bun", len(entries))
+			}
+		})
+	}
+}
+
+// zipArchive returns a zip archive of files.
+func zipArchive(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
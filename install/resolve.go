@@ -0,0 +1,51 @@
+package install
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// BunEnvVar is the environment variable that can point to a Bun executable.
+const BunEnvVar = "GOLTE_BUN"
+
+// ErrBunNotFound is returned by ResolveBun when no Bun executable is available.
+var ErrBunNotFound = errors.New("bun executable not found")
+
+// ResolveOptions controls where ResolveBun looks for Bun.
+type ResolveOptions struct {
+	// Path is an explicit executable path, e.g. from the --bun flag or the project config.
+	Path string
+	// SearchPaths are extra executables or directories checked after the default locations.
+	SearchPaths []string
+	// Version is the Bun version pinned by the project. When set, a matching
+	// executable is preferred over the first one found.
+	Version string
+}
+
+// ResolveBun returns the path of the Bun executable to use without installing anything.
+// An explicit path wins over the GOLTE_BUN environment variable, which wins over
+// PATH, $BUN_INSTALL/bin, ~/.bun/bin and SearchPaths, checked in that order.
+// Every candidate is validated by running `bun --version`. The returned executable
+// is not guaranteed to match opts.Version; use CheckBunVersion for that.
+func ResolveBun(opts ResolveOptions) (string, error) {
+	if opts.Path != "" {
+		return checkExplicitBun(opts.Path, "the configured path")
+	}
+	if path := os.Getenv(BunEnvVar); path != "" {
+		return checkExplicitBun(path, BunEnvVar)
+	}
+
+	if path := findBunVersion(opts.SearchPaths, opts.Version); path != "" {
+		return path, nil
+	}
+	return "", fmt.Errorf("%w: install it from https://bun.sh, run `golte-cli install-bun`, "+
+		"pass --bun <path> or set %s", ErrBunNotFound, BunEnvVar)
+}
+
+func checkExplicitBun(path, source string) (string, error) {
+	if _, err := BunVersion(path); err != nil {
+		return "", fmt.Errorf("bun executable from %s is not usable: %v", source, err)
+	}
+	return path, nil
+}
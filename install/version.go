@@ -0,0 +1,60 @@
+package install
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ProjectBunVersion returns the Bun version pinned by the project's package.json
+// `packageManager` field (e.g. "bun@1.1.34"), or "" if the project does not pin one.
+func ProjectBunVersion(projectPath string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(projectPath, "package.json"))
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read package.json: %v", err)
+	}
+	var pkg struct {
+		PackageManager string `json:"packageManager"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return "", fmt.Errorf("failed to parse package.json: %v", err)
+	}
+	name, version, found := strings.Cut(pkg.PackageManager, "@")
+	if !found || name != "bun" {
+		return "", nil
+	}
+	// 去掉 corepack 可能附加的 hash，例如 bun@1.1.34+sha512.xxx
+	version, _, _ = strings.Cut(version, "+")
+	return normalizeVersion(version), nil
+}
+
+// CheckBunVersion reports an error if the Bun at bunPath is not the wanted version.
+func CheckBunVersion(bunPath, want string) error {
+	if want == "" {
+		return nil
+	}
+	got, err := BunVersion(bunPath)
+	if err != nil {
+		return err
+	}
+	if !SameVersion(got, want) {
+		return fmt.Errorf("project requires bun %s but %s is bun %s; run `golte-cli install-bun --bun-version %s`",
+			want, bunPath, got, want)
+	}
+	return nil
+}
+
+// SameVersion reports whether a and b name the same Bun version, ignoring
+// surrounding spaces and a "v" or "bun-v" prefix.
+func SameVersion(a, b string) bool {
+	return normalizeVersion(a) == normalizeVersion(b)
+}
+
+func normalizeVersion(version string) string {
+	return strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(version), "bun-"), "v")
+}
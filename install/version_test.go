@@ -0,0 +1,21 @@
+package install
+
+import "testing"
+
+func TestSameVersion(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"1.1.34", "1.1.34", true},
+		{"v1.1.34", "1.1.34", true},
+		{" 1.1.34\n", "bun-v1.1.34", true},
+		{"1.1.34", "1.1.35", false},
+		{"1.1.34", "", false},
+	}
+	for _, tt := range tests {
+		if got := SameVersion(tt.a, tt.b); got != tt.want {
+			t.Errorf("SameVersion(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -2,17 +2,33 @@ package main
 
 import (
 	"embed"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
 	"log"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/TimLai666/golte-cli/analysis"
 	"github.com/TimLai666/golte-cli/build"
+	"github.com/TimLai666/golte-cli/config"
 	"github.com/TimLai666/golte-cli/create"
+	"github.com/TimLai666/golte-cli/devserver"
+	"github.com/TimLai666/golte-cli/generate"
 	"github.com/TimLai666/golte-cli/install"
+	"github.com/TimLai666/golte-cli/proc"
+	"github.com/TimLai666/golte-cli/prompt"
 	"github.com/TimLai666/golte-cli/watch"
 )
 
@@ -20,78 +36,421 @@ import (
 var templates embed.FS
 var bunPath string
 
+// projectConfig 是目前命令使用的專案設定
+var projectConfig *config.Config
+
+// terminal 在終端機中詢問問題，所有提問共用同一個 stdin reader
+var terminal = prompt.New(os.Stdin, os.Stdout)
+
+var rootCmd = &cobra.Command{
+	Use:   "golte-cli",
+	Short: "CLI tool for Golte projects",
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println("Welcome to golte-cli! Use `golte-cli help` to see available commands.")
+	},
+}
+
 func init() {
-	var err error
-	bunPath, err = install.InstallBun()
-	if err != nil {
-		log.Fatalf("Failed to install Bun: %v", err)
-	}
+	// Bun 只在需要的命令中解析，不在啟動時安裝
+	rootCmd.PersistentFlags().String("dir", "", "Project directory (default: the nearest parent directory with golte.config.ts or go.mod)")
+	rootCmd.PersistentFlags().String("bun", "", fmt.Sprintf("Path to the Bun executable (overrides %s)", install.BunEnvVar))
+
+	installBunCmd.Flags().String("bun-version", "", "Install this exact Bun release (defaults to the version pinned by the project)")
+	installBunCmd.Flags().String("mirror", "", fmt.Sprintf("Download Bun releases from this URL or directory (overrides %s)", install.MirrorEnvVar))
 
 	// 添加 here flag
-	newCmd.Flags().Bool("here", false, "Create project in current directory")
+	newCmd.Flags().Bool("here", false, "Create project in current directory (or in --dir)")
 
 	// 為需要的命令添加 sveltigo flag
 	newCmd.Flags().Bool("sveltigo", false, "Create a Sveltigo project")
-	buildCmd.Flags().Bool("sveltigo", false, "Build as a Sveltigo project")
-	runCmd.Flags().Bool("sveltigo", false, "Run as a Sveltigo project")
-	devCmd.Flags().Bool("sveltigo", false, "Dev mode for a Sveltigo project")
-}
+	newCmd.Flags().Int("port", 8000, "Default port the app listens on")
+	newCmd.Flags().Bool("typescript", true, "Write the Svelte components in TypeScript")
+	newCmd.Flags().String("css", "none", "CSS framework: "+strings.Join(create.CSSFrameworks, ", "))
+	newCmd.Flags().StringSlice("feature", nil, "Optional features to add: "+strings.Join(create.Features, ", "))
+	newCmd.Flags().BoolP("interactive", "i", false, "Ask for the project settings in a terminal wizard")
+	newCmd.Flags().BoolP("yes", "y", false, "Never prompt, use the flags and defaults")
+	newCmd.MarkFlagsMutuallyExclusive("interactive", "yes")
+	newCmd.Flags().String("router", create.DefaultRouter, "Router to scaffold: "+strings.Join(create.Routers, ", "))
+	newCmd.Flags().Bool("force", false, "With --here, overwrite existing files")
+	newCmd.Flags().Bool("skip-existing", false, "With --here, keep existing files and only write missing ones")
+	newCmd.Flags().Bool("merge", false, "With --here, add Golte to the existing Go module instead of running go mod init")
+	newCmd.MarkFlagsMutuallyExclusive("force", "skip-existing")
+	newCmd.Flags().Bool("keep-on-failure", false, "Keep the partially created project when a step fails")
+	newCmd.Flags().String("module", "", "Go module path of the project (default: the project name in lower case)")
+	newCmd.Flags().String("template", "", "Create the project from a template directory or .tar.gz, .tgz, .tar or .zip archive")
+	newCmd.Flags().StringArray("var", nil, "Set a template variable as name=value (repeatable)")
+	buildCmd.Flags().Bool("sveltigo", false, "Build as a Sveltigo project (detected from the project by default)")
+	runCmd.Flags().Bool("sveltigo", false, "Run as a Sveltigo project (detected from the project by default)")
+	devCmd.Flags().Bool("sveltigo", false, "Dev mode for a Sveltigo project (detected from the project by default)")
 
-func main() {
-	var rootCmd = &cobra.Command{
-		Use:   "golte-cli",
-		Short: "CLI tool for Golte projects",
-		Run: func(cmd *cobra.Command, args []string) {
-			fmt.Println("Welcome to golte-cli! Use `golte-cli help` to see available commands.")
-		},
+	// 覆蓋 golte-cli.toml 中的構建設定
+	for _, c := range []*cobra.Command{buildCmd, runCmd, devCmd} {
+		c.Flags().String("entry", "", "File or package passed to go build (default main.go)")
+		c.Flags().String("output", "", "Path of the built binary (default dist/<name>)")
+	}
+	runCmd.Flags().Duration("grace", 5*time.Second, "Time the app gets to exit after SIGTERM (CTRL_BREAK on Windows) before it is killed")
+	devCmd.Flags().Duration("grace", 5*time.Second, "Time the app gets to exit after SIGTERM (CTRL_BREAK on Windows) before it is killed")
+	devCmd.Flags().Int("port", 3000, "Port of the dev server with live reload")
+	devCmd.Flags().Int("app-port", 8000, "Internal port the app listens on (passed to the app as PORT)")
+	devCmd.Flags().Bool("no-reload", false, "Disable the dev server and browser live reload")
+
+	// 產生頁面、元件與 layout
+	for _, kind := range []generate.Kind{generate.KindPage, generate.KindComponent, generate.KindLayout} {
+		c := newGenerateCmd(kind)
+		c.Flags().Bool("typescript", false, "Use TypeScript (detected from the existing Svelte files by default)")
+		if kind == generate.KindPage {
+			c.Flags().String("path", "", "URL path of the page (default: the kebab-cased name, e.g. /admin/user-list for admin/UserList)")
+			c.Flags().String("method", "GET", "HTTP method of the route ("+strings.Join(generate.Methods, ", ")+")")
+			c.Flags().String("layout", "", "Render the page in this layout under layouts/")
+			c.Flags().Bool("no-route", false, "Only create the Svelte file, without adding a route")
+		}
+		generateCmd.AddCommand(c)
 	}
+	generateCmd.AddCommand(generatePropsCmd)
 
+	routesCmd.Flags().Bool("json", false, "Print the routes as JSON")
+	checkCmd.Flags().Bool("strict", false, "Also fail on warnings like unused pages (overrides strict in golte-cli.toml)")
+	checkCmd.Flags().Bool("json", false, "Print the problems as JSON")
+	buildCmd.Flags().Bool("strict", false, "Also fail the build on check warnings like unused pages (overrides strict in golte-cli.toml)")
+}
+
+func main() {
 	rootCmd.AddCommand(newCmd)
 	rootCmd.AddCommand(buildCmd)
 	rootCmd.AddCommand(runCmd)
 	rootCmd.AddCommand(devCmd)
+	rootCmd.AddCommand(installBunCmd)
+	rootCmd.AddCommand(configCmd)
+	rootCmd.AddCommand(generateCmd)
+	rootCmd.AddCommand(routesCmd)
+	rootCmd.AddCommand(checkCmd)
 	rootCmd.HelpFunc()
 	if err := rootCmd.Execute(); err != nil {
 		log.Fatalf("Error executing command: %v", err)
 	}
 }
 
-// 定義啟動應用程序的函數
-var startApp = func(projectPath, projectName string, isSveltigo bool) *exec.Cmd {
-	// 如果構建失敗，返回 nil
-	if !build.BuildProject(projectPath, projectName, isSveltigo, bunPath) {
-		return nil
+// workingDir 回傳 --dir 指定的目錄，未指定時為目前目錄
+func workingDir(cmd *cobra.Command) string {
+	if dir, _ := cmd.Flags().GetString("dir"); dir != "" {
+		absDir, err := filepath.Abs(dir)
+		if err != nil {
+			log.Fatalf("Invalid --dir: %v", err)
+		}
+		return absDir
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		log.Fatalf("Failed to get current directory: %v", err)
+	}
+	return dir
+}
+
+// findProjectRoot 從工作目錄往上尋找專案根目錄
+func findProjectRoot(cmd *cobra.Command) string {
+	root, err := config.FindRoot(workingDir(cmd))
+	if err != nil {
+		log.Fatalf("Failed to find project root: %v", err)
+	}
+	return root
+}
+
+// loadConfig 讀取專案設定，並以命令列參數覆蓋（參數 > 環境變數 > 設定檔 > 預設值）
+func loadConfig(cmd *cobra.Command, projectPath string) *config.Config {
+	cfg, err := config.Load(projectPath)
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	flags := cmd.Flags()
+	if flags.Changed("sveltigo") {
+		if isSveltigo, _ := flags.GetBool("sveltigo"); isSveltigo {
+			cfg.Flavor = config.FlavorSveltigo
+		} else {
+			cfg.Flavor = config.FlavorGolte
+		}
+	}
+	if flags.Changed("entry") {
+		cfg.Entry, _ = flags.GetString("entry")
+	}
+	if flags.Changed("output") {
+		cfg.Output, _ = flags.GetString("output")
+	}
+	if flags.Changed("bun") {
+		cfg.BunPath, _ = flags.GetString("bun")
+	}
+
+	// 設定的 flavor 與專案實際使用的不同時大聲警告
+	if warning := cfg.FlavorMismatch(); warning != "" {
+		fmt.Fprintf(os.Stderr, "\n!!! WARNING: %s\n\n", warning)
+	}
+	return cfg
+}
+
+// strictMode 回傳 check 的警告是否也會失敗：--strict 優先於 golte-cli.toml 的 [build] strict
+func strictMode(cmd *cobra.Command, cfg *config.Config) bool {
+	if cmd.Flags().Changed("strict") {
+		strict, _ := cmd.Flags().GetBool("strict")
+		return strict
+	}
+	return cfg.Build.Strict
+}
+
+// requireBun 解析 Bun 的路徑，找不到時給出可操作的錯誤訊息並結束
+func requireBun(cfg *config.Config) string {
+	path, err := install.ResolveBun(install.ResolveOptions{
+		Path:        cfg.BunPath,
+		SearchPaths: cfg.BunSearchPaths,
+		Version:     cfg.BunVersion,
+	})
+	if err != nil {
+		log.Fatalf("Failed to resolve Bun: %v", err)
+	}
+	return path
+}
+
+// optionalBun 回傳 Bun 的路徑，找不到時回傳空字串，讀取 golte.config.ts 時會改用解析器
+func optionalBun(cfg *config.Config) string {
+	bunPath, _ := install.ResolveBun(install.ResolveOptions{Path: cfg.BunPath, SearchPaths: cfg.BunSearchPaths})
+	return bunPath
+}
+
+// loadGolteConfig 讀取 golte.config.ts。Bun 只用來執行設定檔，找不到時改用解析器
+func loadGolteConfig(projectPath string, cfg *config.Config) *config.GolteConfig {
+	golteConfig, err := config.LoadGolteConfig(projectPath, optionalBun(cfg))
+	if err != nil {
+		log.Fatalf("Failed to load %s: %v", config.GolteConfigFile, err)
+	}
+	return golteConfig
+}
+
+// buildOptions 將專案設定轉換為構建參數
+func buildOptions(cfg *config.Config) build.Options {
+	return build.Options{
+		ProjectPath: cfg.ProjectPath,
+		ProjectName: cfg.Name,
+		IsSveltigo:  cfg.IsSveltigo(),
+		BunPath:     bunPath,
+		BunVersion:  cfg.BunVersion,
+		Entry:       cfg.Entry,
+		Output:      cfg.Output,
+		Flags:       cfg.Build.Flags,
+		LDFlags:     cfg.Build.LDFlags,
+		Tags:        cfg.Build.Tags,
+	}
+}
+
+// exitOnBuildError 印出構建失敗的輸出與摘要，並以非零狀態碼結束
+func exitOnBuildError(err error) {
+	if err == nil {
+		return
+	}
+	printBuildError(err)
+	os.Exit(1)
+}
+
+// printBuildError 印出失敗階段的命令輸出與錯誤摘要
+func printBuildError(err error) {
+	var buildErr *build.BuildError
+	if errors.As(err, &buildErr) && len(buildErr.Output) > 0 {
+		fmt.Fprintf(os.Stderr, "%s\n", buildErr.Output)
+	}
+	log.Printf("Build failed: %v", err)
+}
+
+// 定義構建應用程序的函數，只執行 plan 需要的階段，輸出到暫存的執行檔，不影響正在執行的版本
+var buildApp = func(projectPath, projectName string, isSveltigo bool, plan watch.Plan) error {
+	opts := buildOptions(projectConfig)
+	opts.Output = build.StagingPath(projectConfig.Output)
+	opts.SkipFrontend = !plan.Frontend
+	opts.SkipModTidy = !plan.ModTidy
+	err := build.BuildProject(opts)
+	if err != nil {
+		printBuildError(err)
 	}
-	cmd := exec.Command(filepath.Join("dist", projectName))
+	return err
+}
+
+// 定義啟動應用程序的函數，將暫存的執行檔換成正式的執行檔後啟動
+var startApp = func(projectPath, projectName string, isSveltigo bool) (*proc.Process, error) {
+	execPath := filepath.Join(projectPath, build.ExecutablePath(projectConfig.Output))
+	stagingPath := filepath.Join(projectPath, build.StagingPath(projectConfig.Output))
+	if err := os.Rename(stagingPath, execPath); err != nil {
+		return nil, fmt.Errorf("failed to install new build: %v", err)
+	}
+	cmd := exec.Command(execPath)
+	cmd.Env = projectConfig.Environ()
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
-	if err := cmd.Start(); err != nil {
-		log.Printf("Failed to start project: %v", err)
+	p, err := proc.Start(cmd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start project: %v", err)
+	}
+	return p, nil
+}
+
+// notifyShutdown 回傳接收 Ctrl+C 與 SIGTERM 的 channel
+func notifyShutdown() <-chan os.Signal {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	return signals
+}
+
+// templateVars 解析 --var name=value 參數
+func templateVars(cmd *cobra.Command) map[string]string {
+	values, _ := cmd.Flags().GetStringArray("var")
+	vars := map[string]string{}
+	for _, value := range values {
+		name, v, ok := strings.Cut(value, "=")
+		if !ok || name == "" {
+			log.Fatalf("Invalid --var %q, expected name=value", value)
+		}
+		vars[name] = v
+	}
+	return vars
+}
+
+// variablePrompt 在終端機中詢問模板變數的值，非互動模式或 --yes 時回傳 nil 以使用預設值
+func variablePrompt(cmd *cobra.Command) func(v create.Variable) (string, error) {
+	if yes, _ := cmd.Flags().GetBool("yes"); yes || !prompt.IsInteractive() {
 		return nil
 	}
-	return cmd
+	return func(v create.Variable) (string, error) {
+		question := v.Prompt
+		if question == "" {
+			question = v.Name
+		}
+		return terminal.Input(question, v.Default, nil)
+	}
+}
+
+// exitOnCreateError 印出建立專案失敗的原因並結束，檔案衝突時提示可用的參數
+func exitOnCreateError(err error) {
+	if err == nil {
+		return
+	}
+	var conflictErr *create.ConflictError
+	if errors.As(err, &conflictErr) {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		hint := "Use --force to overwrite them or --skip-existing to keep them"
+		if slices.Contains(conflictErr.Files, "go.mod") {
+			hint += ", and --merge to add Golte to the existing Go module"
+		}
+		fmt.Fprintln(os.Stderr, hint+".")
+		os.Exit(1)
+	}
+	log.Fatalf("Failed to create project: %v", err)
 }
 
 var newCmd = &cobra.Command{
-	Use:   "new <project-name>",
+	Use:   "new [project-name]",
 	Short: "Create a new Golte sample project",
-	Args:  cobra.ExactArgs(1),
+	Long: `Create a new Golte sample project.
+
+Without a project name, or with -i, new asks for the project settings in a
+terminal wizard and prints the equivalent command line.`,
+	Args: cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
+		// 沒有專案名稱或使用 -i 時執行互動式精靈
+		if interactive, _ := cmd.Flags().GetBool("interactive"); interactive || len(args) == 0 {
+			if interactive && !prompt.IsInteractive() {
+				log.Fatalf("Interactive mode needs a terminal; pass the settings as flags with --yes instead: golte-cli new <project-name> --yes")
+			}
+			if yes, _ := cmd.Flags().GetBool("yes"); yes || !prompt.IsInteractive() {
+				log.Fatalf("A project name is required when not running in a terminal: golte-cli new <project-name>")
+			}
+			args = []string{runNewWizard(cmd, args)}
+		}
 		projectName := args[0]
+		if err := create.ValidateProjectName(projectName); err != nil {
+			log.Fatalf("%v", err)
+		}
+		modulePath, _ := cmd.Flags().GetString("module")
+		if modulePath == "" {
+			modulePath = create.DefaultModulePath(projectName)
+		}
+		if err := create.ValidateModulePath(modulePath); err != nil {
+			log.Fatalf("Invalid --module: %v", err)
+		}
+		baseDir := workingDir(cmd)
+		// 目前目錄可能是其他專案，只讀取環境變數與 --bun，不載入它的 golte-cli.toml
+		envConfig := config.FromEnv()
+		if cmd.Flags().Changed("bun") {
+			envConfig.BunPath, _ = cmd.Flags().GetString("bun")
+		}
+		bunPath = requireBun(envConfig)
 		fmt.Println("Creating project, please wait...")
 		inCurrentDir := cmd.Flag("here").Value.String() == "true"
 		isSveltigo := cmd.Flag("sveltigo").Value.String() == "true"
-		create.CreateProject(projectName, templates, inCurrentDir, isSveltigo, bunPath)
-		var projectPath string
-		var err error
-		projectPath, err = os.Getwd()
+		router, err := create.NormalizeRouter(cmd.Flag("router").Value.String())
 		if err != nil {
-			log.Fatalf("Failed to get current directory: %v", err)
+			log.Fatalf("Invalid --router: %v", err)
+		}
+		flavor := config.FlavorGolte
+		if isSveltigo {
+			flavor = config.FlavorSveltigo
+		}
+		port, _ := cmd.Flags().GetInt("port")
+		typeScript, _ := cmd.Flags().GetBool("typescript")
+		css, err := create.NormalizeCSS(cmd.Flag("css").Value.String())
+		if err != nil {
+			log.Fatalf("Invalid --css: %v", err)
+		}
+		features, _ := cmd.Flags().GetStringSlice("feature")
+		if err := create.ValidateFeatures(features); err != nil {
+			log.Fatalf("Invalid --feature: %v", err)
+		}
+		opts := create.Options{
+			BaseDir:      baseDir,
+			InCurrentDir: inCurrentDir,
+			BunPath:      bunPath,
+			TemplateData: create.TemplateData{
+				ModulePath:  modulePath,
+				ProjectName: projectName,
+				Flavor:      flavor,
+				Router:      router,
+				Port:        port,
+				TypeScript:  typeScript,
+				CSS:         css,
+				Features:    features,
+			},
+		}
+		opts.KeepOnFailure, _ = cmd.Flags().GetBool("keep-on-failure")
+		opts.Merge, _ = cmd.Flags().GetBool("merge")
+		if force, _ := cmd.Flags().GetBool("force"); force {
+			opts.OnConflict = create.ConflictOverwrite
+		} else if skip, _ := cmd.Flags().GetBool("skip-existing"); skip {
+			opts.OnConflict = create.ConflictSkip
+		}
+		if source, _ := cmd.Flags().GetString("template"); source != "" {
+			tmpl, err := create.LoadTemplate(source)
+			if err != nil {
+				log.Fatalf("Failed to load template: %v", err)
+			}
+			if tmpl.Manifest.Name != "" {
+				fmt.Printf("Using template %s\n", tmpl.Manifest.Name)
+			}
+			opts.Templates = tmpl.FS
+			opts.PostCreate = tmpl.Manifest.PostCreate
+			opts.Vars, err = tmpl.ResolveVariables(templateVars(cmd), variablePrompt(cmd))
+			if err == nil {
+				err = create.CreateProject(opts)
+			}
+			tmpl.Close()
+			exitOnCreateError(err)
+		} else {
+			opts.Templates, err = fs.Sub(templates, "templates")
+			if err != nil {
+				log.Fatalf("Failed to read embedded templates: %v", err)
+			}
+			exitOnCreateError(create.CreateProject(opts))
 		}
+		projectPath := baseDir
 		if !inCurrentDir {
-			projectPath = filepath.Join(projectPath, projectName)
+			projectPath = filepath.Join(baseDir, projectName)
 		}
-		build.BuildProject(projectPath, projectName, isSveltigo, bunPath)
+		projectConfig = loadConfig(cmd, projectPath)
+		exitOnBuildError(build.BuildProject(buildOptions(projectConfig)))
 		fmt.Printf("Project '%s' created successfully!\n", projectName)
 	},
 }
@@ -100,14 +459,15 @@ var buildCmd = &cobra.Command{
 	Use:   "build",
 	Short: "Build the project",
 	Run: func(cmd *cobra.Command, args []string) {
-		projectPath, err := os.Getwd()
-		if err != nil {
-			log.Fatalf("Failed to get current directory: %v", err)
-		}
-		projectName := filepath.Base(projectPath)
+		projectPath := findProjectRoot(cmd)
+		projectConfig = loadConfig(cmd, projectPath)
+		bunPath = requireBun(projectConfig)
 		fmt.Println("Building the project...")
-		isSveltigo := cmd.Flag("sveltigo").Value.String() == "true"
-		build.BuildProject(projectPath, projectName, isSveltigo, bunPath)
+		opts := buildOptions(projectConfig)
+		opts.Check = true
+		opts.Strict = strictMode(cmd, projectConfig)
+		exitOnBuildError(build.BuildProject(opts))
+		fmt.Println("Build completed")
 	},
 }
 
@@ -115,28 +475,40 @@ var runCmd = &cobra.Command{
 	Use:   "run",
 	Short: "Build and run the project",
 	Run: func(cmd *cobra.Command, args []string) {
-		projectPath, err := os.Getwd()
-		if err != nil {
-			log.Fatalf("Failed to get current directory: %v", err)
-		}
-		projectName := filepath.Base(projectPath)
+		projectPath := findProjectRoot(cmd)
+		projectConfig = loadConfig(cmd, projectPath)
+		bunPath = requireBun(projectConfig)
 		fmt.Println("Building the project...")
-		isSveltigo := cmd.Flag("sveltigo").Value.String() == "true"
-		build.BuildProject(projectPath, projectName, isSveltigo, bunPath)
+		exitOnBuildError(build.BuildProject(buildOptions(projectConfig)))
 		fmt.Println("Running the project...")
 
 		// 創建一個新的命令
-		command := exec.Command(filepath.Join("dist", projectName))
+		command := exec.Command(filepath.Join(projectPath, build.ExecutablePath(projectConfig.Output)))
+		command.Env = projectConfig.Environ()
 
 		// 將命令的標準輸出和標準錯誤直接連接到當前程序
 		command.Stdout = os.Stdout
 		command.Stderr = os.Stderr
 
-		// 執行命令
-		err = command.Run()
+		// 在獨立的 process group 執行命令，並轉送 Ctrl+C 與 SIGTERM
+		signals := notifyShutdown()
+		p, err := proc.Start(command)
 		if err != nil {
 			log.Fatalf("Failed to run project: %v", err)
 		}
+		grace, _ := cmd.Flags().GetDuration("grace")
+		select {
+		case <-p.Done():
+		case sig := <-signals:
+			fmt.Printf("\nReceived %v, stopping...\n", sig)
+			_ = p.Stop(sig, grace)
+		}
+		if err := p.Wait(); err != nil {
+			log.Printf("Project exited: %v", err)
+			if code := p.ExitCode(); code > 0 {
+				os.Exit(code)
+			}
+		}
 	},
 }
 
@@ -144,12 +516,289 @@ var devCmd = &cobra.Command{
 	Use:   "dev",
 	Short: "Run the project and auto rebuild when changes",
 	Run: func(cmd *cobra.Command, args []string) {
-		projectPath, err := os.Getwd()
+		projectPath := findProjectRoot(cmd)
+		projectConfig = loadConfig(cmd, projectPath)
+		bunPath = requireBun(projectConfig)
+		golteConfig, err := config.LoadGolteConfig(projectPath, bunPath)
 		if err != nil {
-			log.Fatalf("Failed to get current directory: %v", err)
+			log.Fatalf("Failed to load %s: %v", config.GolteConfigFile, err)
+		}
+
+		opts := watch.Options{
+			ProjectPath: projectPath,
+			ProjectName: projectConfig.Name,
+			IsSveltigo:  projectConfig.IsSveltigo(),
+			Build:       buildApp,
+			Start:       startApp,
+			Extensions:  projectConfig.Watch.Extensions,
+			Exclude:     projectConfig.Watch.Exclude,
+			OutDir:      golteConfig.OutPath(),
+			Signals:     notifyShutdown(),
+		}
+		opts.Grace, _ = cmd.Flags().GetDuration("grace")
+
+		// 應用程式監聽內部埠，透過 PORT 環境變數傳給子程序
+		appPort, _ := cmd.Flags().GetInt("app-port")
+		if err := os.Setenv("PORT", strconv.Itoa(appPort)); err != nil {
+			log.Fatalf("Failed to set PORT: %v", err)
+		}
+
+		// 啟動 dev server，在穩定的埠代理到應用程式，重新構建時暫停請求並在完成後通知瀏覽器重新整理
+		if noReload, _ := cmd.Flags().GetBool("no-reload"); !noReload {
+			port, _ := cmd.Flags().GetInt("port")
+			server, err := devserver.New(fmt.Sprintf("http://localhost:%d", appPort))
+			if err != nil {
+				log.Fatalf("Failed to create dev server: %v", err)
+			}
+			go func() {
+				if err := server.ListenAndServe(fmt.Sprintf(":%d", port)); err != nil {
+					log.Fatalf("Dev server failed: %v", err)
+				}
+			}()
+			fmt.Printf("Dev server with live reload on http://localhost:%d\n", port)
+			opts.BeforeSwap = server.Hold
+			opts.AfterSwap = server.Resume
+		}
+
+		watch.WatchAndRebuild(opts)
+	},
+}
+
+var installBunCmd = &cobra.Command{
+	Use:   "install-bun",
+	Short: "Install Bun if it is not available yet",
+	Run: func(cmd *cobra.Command, args []string) {
+		// 不在專案中時也可以安裝 Bun
+		projectPath, err := config.FindRoot(workingDir(cmd))
+		if err != nil {
+			projectPath = workingDir(cmd)
+		}
+		cfg := loadConfig(cmd, projectPath)
+		version := cfg.BunVersion
+		if cmd.Flags().Changed("bun-version") {
+			version, _ = cmd.Flags().GetString("bun-version")
+		}
+		mirror := cfg.BunMirror
+		if cmd.Flags().Changed("mirror") {
+			mirror, _ = cmd.Flags().GetString("mirror")
+		}
+
+		var path string
+		if version != "" {
+			// 下載指定版本並驗證 SHA-256
+			// 固定的 checksum 只適用於設定檔中的版本
+			var checksums map[string]string
+			if install.SameVersion(version, cfg.BunVersion) {
+				checksums = cfg.BunSHA256
+			}
+			path, err = install.InstallBunRelease(version, mirror, checksums)
+		} else {
+			path, err = install.InstallBun()
+		}
+		if err != nil {
+			log.Fatalf("Failed to install Bun: %v", err)
+		}
+		fmt.Printf("Bun is available at %s\n", path)
+	},
+}
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Print the resolved project configuration",
+	Run: func(cmd *cobra.Command, args []string) {
+		projectPath := findProjectRoot(cmd)
+		cfg := loadConfig(cmd, projectPath)
+		if cfg.File != "" {
+			fmt.Printf("# Loaded from %s\n", cfg.File)
+		} else {
+			fmt.Printf("# No %s found, using defaults\n", config.FileName)
+		}
+		fmt.Print(cfg.String())
+	},
+}
+
+var generateCmd = &cobra.Command{
+	Use:     "generate",
+	Aliases: []string{"g"},
+	Short:   "Generate pages, components and layouts",
+}
+
+// newGenerateCmd 建立產生指定種類 Svelte 檔案的子命令
+func newGenerateCmd(kind generate.Kind) *cobra.Command {
+	short := map[generate.Kind]string{
+		generate.KindPage:      "Create a page under pages/ and add its route to router/defineRoutes.go",
+		generate.KindComponent: "Create a component under components/",
+		generate.KindLayout:    "Create a layout under layouts/",
+	}[kind]
+	return &cobra.Command{
+		Use:   string(kind) + " <name>",
+		Short: short,
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			projectPath := findProjectRoot(cmd)
+			cfg := loadConfig(cmd, projectPath)
+			golteConfig := loadGolteConfig(projectPath, cfg)
+
+			opts := generate.Options{
+				ProjectPath: projectPath,
+				SrcDir:      golteConfig.SrcPath(),
+				Kind:        kind,
+				Name:        args[0],
+				TypeScript:  generate.DetectTypeScript(golteConfig.SrcPath()),
+				Flavor:      cfg.Flavor,
+			}
+			if cmd.Flags().Changed("typescript") {
+				opts.TypeScript, _ = cmd.Flags().GetBool("typescript")
+			}
+			if kind == generate.KindPage {
+				opts.Route, _ = cmd.Flags().GetString("path")
+				opts.Method, _ = cmd.Flags().GetString("method")
+				opts.Layout, _ = cmd.Flags().GetString("layout")
+				opts.NoRoute, _ = cmd.Flags().GetBool("no-route")
+			}
+
+			files, err := generate.Generate(opts)
+			if err != nil {
+				log.Fatalf("Failed to generate %s: %v", kind, err)
+			}
+			for i, file := range files {
+				if rel, err := filepath.Rel(projectPath, file); err == nil {
+					file = rel
+				}
+				if i == 0 {
+					fmt.Printf("Created %s\n", file)
+				} else {
+					fmt.Printf("Updated %s\n", file)
+				}
+			}
+		},
+	}
+}
+
+var generatePropsCmd = &cobra.Command{
+	Use:   "props",
+	Short: "Generate TypeScript types for the props the Go code passes to each page",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		projectPath := findProjectRoot(cmd)
+		golteConfig := loadGolteConfig(projectPath, loadConfig(cmd, projectPath))
+		written, skipped, err := generate.GenerateProps(projectPath, golteConfig.SrcPath())
+		for _, file := range written {
+			if rel, err := filepath.Rel(projectPath, file); err == nil {
+				file = rel
+			}
+			fmt.Printf("Wrote %s\n", file)
+		}
+		if err != nil {
+			log.Fatalf("Failed to generate props: %v", err)
+		}
+		for _, component := range skipped {
+			fmt.Fprintf(os.Stderr, "warning: skipped %s: the props passed to RenderPage are not a map or struct literal\n", component)
+		}
+		if len(written) == 0 {
+			fmt.Println("Props are up to date")
+		}
+	},
+}
+
+var routesCmd = &cobra.Command{
+	Use:   "routes",
+	Short: "List the routes of the project and the pages they render",
+	Run: func(cmd *cobra.Command, args []string) {
+		projectPath := findProjectRoot(cmd)
+		golteConfig := loadGolteConfig(projectPath, loadConfig(cmd, projectPath))
+
+		// 靜態分析 Go 程式碼，不需要構建或執行專案
+		project, err := analysis.Load(projectPath)
+		if err != nil {
+			log.Fatalf("Failed to analyse the project: %v", err)
+		}
+		routes := project.Routes()
+		srcDir := golteConfig.SrcPath()
+
+		type routeJSON struct {
+			analysis.Route
+			Missing bool `json:"missing,omitempty"`
+		}
+		var missing []analysis.Route
+		entries := make([]routeJSON, len(routes))
+		for i, r := range routes {
+			entries[i] = routeJSON{Route: r}
+			if r.Component != "" && !analysis.ComponentExists(srcDir, r.Component) {
+				entries[i].Missing = true
+				missing = append(missing, r)
+			}
+		}
+
+		if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(entries); err != nil {
+				log.Fatalf("Failed to write JSON: %v", err)
+			}
+		} else {
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "METHOD\tPATH\tCOMPONENT\tHANDLER")
+			for _, entry := range entries {
+				component := entry.Component
+				if component == "" {
+					component = "-"
+				}
+				if len(entry.Layouts) > 0 {
+					component += " (in " + strings.Join(entry.Layouts, ", ") + ")"
+				}
+				if entry.Missing {
+					component += " (missing)"
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", entry.Method, entry.Path, component, entry.Handler)
+			}
+			w.Flush()
+		}
+
+		for _, r := range missing {
+			fmt.Fprintf(os.Stderr, "warning: %s %s renders %s, but %s does not exist (%s)\n",
+				r.Method, r.Path, r.Component, analysis.ComponentFile(golteConfig.SrcDir, r.Component), r.Registered)
+		}
+	},
+}
+
+var checkCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Check that the pages and layouts rendered by the Go code exist",
+	Run: func(cmd *cobra.Command, args []string) {
+		projectPath := findProjectRoot(cmd)
+		cfg := loadConfig(cmd, projectPath)
+		issues, err := build.CheckComponents(projectPath, optionalBun(cfg))
+		if err != nil {
+			log.Fatalf("Failed to check the project: %v", err)
+		}
+
+		errorCount := 0
+		for _, issue := range issues {
+			if issue.Severity == analysis.SeverityError {
+				errorCount++
+			}
+		}
+		if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			if issues == nil {
+				issues = []analysis.Issue{}
+			}
+			if err := encoder.Encode(issues); err != nil {
+				log.Fatalf("Failed to write JSON: %v", err)
+			}
+		} else if len(issues) == 0 {
+			fmt.Println("No problems found")
+		} else {
+			for _, issue := range issues {
+				fmt.Println(issue)
+			}
+			fmt.Printf("%d error(s), %d warning(s)\n", errorCount, len(issues)-errorCount)
+		}
+
+		if build.CheckFailed(issues, strictMode(cmd, cfg)) != nil {
+			os.Exit(1)
 		}
-		projectName := filepath.Base(projectPath)
-		isSveltigo := cmd.Flag("sveltigo").Value.String() == "true"
-		watch.WatchAndRebuild(projectPath, projectName, startApp, isSveltigo)
 	},
 }
@@ -0,0 +1,71 @@
+package proc
+
+import (
+	"os"
+	"os/exec"
+	"time"
+)
+
+// Process is an app process started in its own process group, so that stopping
+// it also stops the child processes it spawned.
+type Process struct {
+	cmd  *exec.Cmd
+	done chan struct{}
+	err  error
+}
+
+// Start starts cmd in a new process group.
+func Start(cmd *exec.Cmd) (*Process, error) {
+	setProcessGroup(cmd)
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	p := &Process{cmd: cmd, done: make(chan struct{})}
+	go func() {
+		p.err = cmd.Wait()
+		close(p.done)
+	}()
+	return p, nil
+}
+
+// Done is closed when the process has exited.
+func (p *Process) Done() <-chan struct{} {
+	return p.done
+}
+
+// Wait waits for the process to exit and returns its error, like exec.Cmd.Wait.
+func (p *Process) Wait() error {
+	<-p.done
+	return p.err
+}
+
+// ExitCode returns the exit code of an exited process, or -1.
+func (p *Process) ExitCode() int {
+	if p.cmd.ProcessState == nil {
+		return -1
+	}
+	return p.cmd.ProcessState.ExitCode()
+}
+
+// Stop sends sig to the process group and waits up to grace for the process to
+// exit before killing the whole group. On Windows a CTRL_BREAK_EVENT is sent
+// instead, which Go programs receive as os.Interrupt.
+func (p *Process) Stop(sig os.Signal, grace time.Duration) error {
+	select {
+	case <-p.done:
+		return p.err
+	default:
+	}
+
+	if err := signalGroup(p.cmd, sig); err != nil {
+		killGroup(p.cmd)
+		return p.Wait()
+	}
+	select {
+	case <-p.done:
+	case <-time.After(grace):
+		killGroup(p.cmd)
+		<-p.done
+	}
+	return p.err
+}
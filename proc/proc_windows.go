@@ -0,0 +1,36 @@
+//go:build windows
+
+package proc
+
+import (
+	"os"
+	"os/exec"
+	"strconv"
+	"syscall"
+)
+
+func setProcessGroup(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.CreationFlags |= syscall.CREATE_NEW_PROCESS_GROUP
+}
+
+var generateConsoleCtrlEvent = syscall.NewLazyDLL("kernel32.dll").NewProc("GenerateConsoleCtrlEvent")
+
+// signalGroup 在 Windows 上無法傳送 SIGTERM，改為對程序群組送出 CTRL_BREAK_EVENT；
+// 程序以 CREATE_NEW_PROCESS_GROUP 啟動，群組 id 即為其 pid。Go 程式會收到 os.Interrupt
+func signalGroup(cmd *exec.Cmd, sig os.Signal) error {
+	r, _, err := generateConsoleCtrlEvent.Call(syscall.CTRL_BREAK_EVENT, uintptr(cmd.Process.Pid))
+	if r == 0 {
+		return err
+	}
+	return nil
+}
+
+// killGroup 用 taskkill /T 終止程序及其子程序
+func killGroup(cmd *exec.Cmd) {
+	if err := exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(cmd.Process.Pid)).Run(); err != nil {
+		_ = cmd.Process.Kill()
+	}
+}
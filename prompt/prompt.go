@@ -0,0 +1,153 @@
+// Package prompt asks questions on a terminal.
+package prompt
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// IsInteractive reports whether stdin is a terminal.
+func IsInteractive() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// Prompter reads answers from in and writes questions to out.
+type Prompter struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+// New returns a Prompter for in and out.
+func New(in io.Reader, out io.Writer) *Prompter {
+	return &Prompter{in: bufio.NewReader(in), out: out}
+}
+
+// readLine reads one trimmed line. The last line may end without a newline.
+func (p *Prompter) readLine() (string, error) {
+	line, err := p.in.ReadString('\n')
+	if err != nil && (line == "" || !errors.Is(err, io.EOF)) {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// Input asks for a value. An empty answer selects def. If validate is set, the
+// question is repeated until it accepts the answer.
+func (p *Prompter) Input(question, def string, validate func(string) error) (string, error) {
+	for {
+		if def != "" {
+			fmt.Fprintf(p.out, "%s [%s]: ", question, def)
+		} else {
+			fmt.Fprintf(p.out, "%s: ", question)
+		}
+		answer, err := p.readLine()
+		if err != nil {
+			return "", err
+		}
+		if answer == "" {
+			answer = def
+		}
+		if validate != nil {
+			if err := validate(answer); err != nil {
+				fmt.Fprintf(p.out, "  %v\n", err)
+				continue
+			}
+		}
+		return answer, nil
+	}
+}
+
+// Confirm asks a yes/no question.
+func (p *Prompter) Confirm(question string, def bool) (bool, error) {
+	hint := "y/N"
+	if def {
+		hint = "Y/n"
+	}
+	for {
+		fmt.Fprintf(p.out, "%s [%s]: ", question, hint)
+		answer, err := p.readLine()
+		if err != nil {
+			return false, err
+		}
+		switch strings.ToLower(answer) {
+		case "":
+			return def, nil
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+		fmt.Fprintln(p.out, "  Please answer y or n")
+	}
+}
+
+// Select asks to choose one of options, by number or by name.
+func (p *Prompter) Select(question string, options []string, def string) (string, error) {
+	fmt.Fprintln(p.out, question)
+	for i, option := range options {
+		fmt.Fprintf(p.out, "  %d) %s\n", i+1, option)
+	}
+	answer, err := p.Input("Choose", def, func(answer string) error {
+		if _, ok := choose(options, answer); !ok {
+			return fmt.Errorf("choose a number between 1 and %d", len(options))
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	option, _ := choose(options, answer)
+	return option, nil
+}
+
+// MultiSelect asks to choose any number of options, as a comma separated list
+// of numbers or names. "none" selects nothing.
+func (p *Prompter) MultiSelect(question string, options []string, defaults []string) ([]string, error) {
+	fmt.Fprintln(p.out, question)
+	for i, option := range options {
+		fmt.Fprintf(p.out, "  %d) %s\n", i+1, option)
+	}
+	def := strings.Join(defaults, ",")
+	if def == "" {
+		def = "none"
+	}
+	var selected []string
+	_, err := p.Input("Choose (comma separated)", def, func(answer string) error {
+		selected = nil
+		if answer == "none" {
+			return nil
+		}
+		for _, part := range strings.Split(answer, ",") {
+			option, ok := choose(options, strings.TrimSpace(part))
+			if !ok {
+				return fmt.Errorf("unknown choice %q", strings.TrimSpace(part))
+			}
+			if !slices.Contains(selected, option) {
+				selected = append(selected, option)
+			}
+		}
+		return nil
+	})
+	return selected, err
+}
+
+// choose returns the option answer refers to by its 1-based number or name.
+func choose(options []string, answer string) (string, bool) {
+	if n, err := strconv.Atoi(answer); err == nil {
+		if n >= 1 && n <= len(options) {
+			return options[n-1], true
+		}
+		return "", false
+	}
+	if slices.Contains(options, answer) {
+		return answer, true
+	}
+	return "", false
+}
@@ -0,0 +1,103 @@
+package watch
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Plan describes which optional build stages a set of changes requires.
+// The Go binary is always rebuilt.
+type Plan struct {
+	Frontend bool // run the frontend build (bun x golte)
+	ModTidy  bool // run go mod tidy
+}
+
+// fullPlan runs every stage; it is used for the first build.
+var fullPlan = Plan{Frontend: true, ModTidy: true}
+
+// frontendConfigFiles 變更時需要重新構建前端
+var frontendConfigFiles = map[string]bool{
+	"golte.config.ts":  true,
+	"svelte.config.js": true,
+	"package.json":     true,
+	"bun.lockb":        true,
+	"bun.lock":         true,
+}
+
+// importCache 記錄每個 Go 檔案的 import，用來判斷是否需要 go mod tidy
+type importCache map[string]string
+
+// scan 讀取 root 底下所有 Go 檔案的 import，略過 skipDirs 與 outDir
+func (c importCache) scan(root string, skipDirs []string, outDir string) {
+	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			if path != root && (slices.Contains(skipDirs, info.Name()) || path == filepath.Clean(outDir)) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(path) == ".go" {
+			c[path] = readImports(path)
+		}
+		return nil
+	})
+}
+
+// changed 更新 path 的 import 並回報是否與上次不同
+func (c importCache) changed(path string) bool {
+	imports := readImports(path)
+	previous, known := c[path]
+	if imports == "" {
+		delete(c, path)
+	} else {
+		c[path] = imports
+	}
+	return !known || previous != imports
+}
+
+// readImports 回傳排序後的 import 路徑；檔案不存在或無法解析時回傳空字串
+func readImports(path string) string {
+	file, err := parser.ParseFile(token.NewFileSet(), path, nil, parser.ImportsOnly)
+	if err != nil {
+		return ""
+	}
+	imports := make([]string, 0, len(file.Imports))
+	for _, spec := range file.Imports {
+		importPath, _ := strconv.Unquote(spec.Path.Value)
+		imports = append(imports, importPath)
+	}
+	slices.Sort(imports)
+	return strings.Join(imports, "\n")
+}
+
+// classify 依變更的檔案決定需要執行哪些構建階段
+func classify(changedPaths []string, imports importCache) Plan {
+	var plan Plan
+	for _, path := range changedPaths {
+		base := filepath.Base(path)
+		switch {
+		case base == "go.mod" || base == "go.sum":
+			plan.ModTidy = true
+		case frontendConfigFiles[base]:
+			plan.Frontend = true
+		case filepath.Ext(path) == ".go":
+			if imports.changed(path) {
+				plan.ModTidy = true
+			}
+		case filepath.Ext(path) == "":
+			// 目錄的新增、刪除或重新命名，無法判斷內容，全部重建
+			return fullPlan
+		default:
+			plan.Frontend = true
+		}
+	}
+	return plan
+}
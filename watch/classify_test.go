@@ -0,0 +1,119 @@
+package watch
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestClassify(t *testing.T) {
+	const mainGo = "package main\n\nimport \"fmt\"\n\nfunc main() { fmt.Println() }\n"
+	tests := []struct {
+		name string
+		// before are the files when the imports are scanned, after the
+		// files written or, if empty, removed before classifying.
+		before  map[string]string
+		after   map[string]string
+		changed []string
+		want    Plan
+	}{
+		{
+			name:    "go body change",
+			before:  map[string]string{"main.go": mainGo},
+			after:   map[string]string{"main.go": "package main\n\nimport \"fmt\"\n\nfunc main() { fmt.Println(1) }\n"},
+			changed: []string{"main.go"},
+			want:    Plan{},
+		},
+		{
+			name:    "go import added",
+			before:  map[string]string{"main.go": mainGo},
+			after:   map[string]string{"main.go": "package main\n\nimport (\n\t\"fmt\"\n\t\"os\"\n)\n\nfunc main() { fmt.Println(os.Args) }\n"},
+			changed: []string{"main.go"},
+			want:    Plan{ModTidy: true},
+		},
+		{
+			name:    "go imports reordered",
+			before:  map[string]string{"main.go": "package main\n\nimport (\n\t\"fmt\"\n\t\"os\"\n)\n"},
+			after:   map[string]string{"main.go": "package main\n\nimport (\n\t\"os\"\n\t\"fmt\"\n)\n"},
+			changed: []string{"main.go"},
+			want:    Plan{},
+		},
+		{
+			name:    "new go file",
+			after:   map[string]string{"handlers/api.go": "package handlers\n\nimport \"net/http\"\n"},
+			changed: []string{"handlers/api.go"},
+			want:    Plan{ModTidy: true},
+		},
+		{
+			name:    "go file removed",
+			before:  map[string]string{"main.go": mainGo},
+			after:   map[string]string{"main.go": ""},
+			changed: []string{"main.go"},
+			want:    Plan{ModTidy: true},
+		},
+		{
+			name:    "go.mod",
+			changed: []string{"go.mod"},
+			want:    Plan{ModTidy: true},
+		},
+		{
+			name:    "svelte file",
+			changed: []string{"web/pages/App.svelte"},
+			want:    Plan{Frontend: true},
+		},
+		{
+			name:    "frontend config",
+			changed: []string{"golte.config.ts", "bun.lock"},
+			want:    Plan{Frontend: true},
+		},
+		{
+			name:    "svelte and go body change",
+			before:  map[string]string{"main.go": mainGo},
+			after:   map[string]string{"main.go": mainGo},
+			changed: []string{"web/app.html", "main.go"},
+			want:    Plan{Frontend: true},
+		},
+		{
+			name:    "directory",
+			changed: []string{"web/pages/admin"},
+			want:    fullPlan,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			writeFiles(t, root, tt.before)
+			imports := importCache{}
+			imports.scan(root, nil, filepath.Join(root, "build"))
+			writeFiles(t, root, tt.after)
+
+			var changed []string
+			for _, path := range tt.changed {
+				changed = append(changed, filepath.Join(root, path))
+			}
+			if got := classify(changed, imports); got != tt.want {
+				t.Errorf("classify() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// writeFiles writes files under root, removing the ones with empty content.
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if content == "" {
+			if err := os.Remove(path); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
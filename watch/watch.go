@@ -4,34 +4,78 @@ import (
 	"fmt"
 	"log"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"slices"
 	"strings"
-	"sync/atomic"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/fsnotify/fsnotify"
+
+	"github.com/TimLai666/golte-cli/config"
+	"github.com/TimLai666/golte-cli/proc"
 )
 
+// debounceDelay is how long the watcher waits for file events to settle before rebuilding.
+const debounceDelay = 300 * time.Millisecond
+
 type watchPaths struct {
 	configPath string
 }
 
-func WatchAndRebuild(projectPath, projectName string, startApp func(projectPath, projectName string, isSveltigo bool) *exec.Cmd, isSveltigo bool) {
+// Options configures WatchAndRebuild.
+type Options struct {
+	ProjectPath string
+	ProjectName string
+	IsSveltigo  bool
+	// Build builds the app into a staging binary without touching the running one,
+	// running only the stages the plan asks for.
+	Build func(projectPath, projectName string, isSveltigo bool, plan Plan) error
+	// Start installs the staged binary and starts it.
+	Start func(projectPath, projectName string, isSveltigo bool) (*proc.Process, error)
+	// Grace is how long the app gets to exit after SIGTERM before it is killed.
+	Grace time.Duration
+	// Extensions are the file extensions that trigger a rebuild.
+	Extensions []string
+	// Exclude are directory names that are neither watched nor trigger rebuilds.
+	Exclude []string
+	// OutDir is the frontend build output directory from golte.config.ts. It is
+	// generated by the build and therefore never watched.
+	OutDir string
+	// Signals stops watching when a signal arrives; the signal is forwarded to the app.
+	Signals <-chan os.Signal
+	// BeforeSwap, if set, is called after a successful build, before the running
+	// app is stopped.
+	BeforeSwap func()
+	// AfterSwap, if set, is called after every BeforeSwap once the new app has
+	// been started or the swap was abandoned; started reports whether the new
+	// process is running.
+	AfterSwap func(started bool)
+}
+
+func WatchAndRebuild(opts Options) {
+	projectPath, projectName, isSveltigo := opts.ProjectPath, opts.ProjectName, opts.IsSveltigo
 	paths := &watchPaths{
-		configPath: filepath.Join(projectPath, "golte.config.ts"),
+		configPath: filepath.Join(projectPath, config.GolteConfigFile),
 	}
 
-	var currentCmd *exec.Cmd
-	processChannel := make(chan *exec.Cmd, 1)
-	isRebuilding := atomic.Bool{}
+	// current 是正在執行的程序，stopped 表示 CLI 正在結束，不再啟動新程序
+	var (
+		procMu  sync.Mutex
+		current *proc.Process
+		stopped bool
+	)
+
+	isOutDir := func(path string) bool {
+		return opts.OutDir != "" && filepath.Clean(path) == filepath.Clean(opts.OutDir)
+	}
 
 	setupWatchers := func(watcher *fsnotify.Watcher) error {
 		for _, watchPath := range watcher.WatchList() {
 			watcher.Remove(watchPath)
 		}
 
-		dirsNotToWatch := []string{"node_modules", "dist", ".git", "build"}
 		var dirsToWatch []string
 
 		err := filepath.Walk(projectPath, func(path string, info os.FileInfo, err error) error {
@@ -41,7 +85,7 @@ func WatchAndRebuild(projectPath, projectName string, startApp func(projectPath,
 
 			if info.IsDir() {
 				baseName := info.Name()
-				if slices.Contains(dirsNotToWatch, baseName) {
+				if path != projectPath && (slices.Contains(opts.Exclude, baseName) || isOutDir(path)) {
 					fmt.Printf("Skipping directory: %s\n", path)
 					return filepath.SkipDir
 				}
@@ -66,19 +110,71 @@ func WatchAndRebuild(projectPath, projectName string, startApp func(projectPath,
 		return watcher.Add(paths.configPath)
 	}
 
-	startAndMonitor := func() bool {
-		cmd := startApp(projectPath, projectName, isSveltigo)
-		if cmd == nil {
-			log.Println("Failed to start app, waiting for next file change...")
+	// buildAndSwap 先構建到暫存執行檔，成功後才停止舊程序並啟動新程序；
+	// 構建失敗時舊版本會繼續執行
+	buildAndSwap := func(plan Plan) bool {
+		if err := opts.Build(projectPath, projectName, isSveltigo, plan); err != nil {
+			procMu.Lock()
+			running := current != nil
+			procMu.Unlock()
+			if running {
+				log.Println("Build failed, keeping the previous version running. Waiting for next file change...")
+			} else {
+				log.Println("Build failed, waiting for next file change...")
+			}
 			return false
 		}
 
-		currentCmd = cmd
-		processChannel <- cmd
+		if opts.BeforeSwap != nil {
+			opts.BeforeSwap()
+		}
+
+		procMu.Lock()
+		if stopped {
+			procMu.Unlock()
+			// BeforeSwap 已被呼叫，仍需呼叫 AfterSwap 釋放等待中的請求
+			if opts.AfterSwap != nil {
+				opts.AfterSwap(false)
+			}
+			return false
+		}
+		if current != nil {
+			_ = current.Stop(syscall.SIGTERM, opts.Grace)
+			current = nil
+		}
+
+		p, err := opts.Start(projectPath, projectName, isSveltigo)
+		if err == nil {
+			current = p
+		}
+		procMu.Unlock()
+
+		if opts.AfterSwap != nil {
+			opts.AfterSwap(err == nil)
+		}
+		if err != nil {
+			log.Printf("Failed to start app: %v, waiting for next file change...", err)
+			return false
+		}
 		return true
 	}
 
-	startAndMonitor()
+	// shutdown 將訊號轉送給應用程式，等待它結束後停止監看
+	shutdown := func(sig os.Signal) {
+		fmt.Printf("\nReceived %v, stopping...\n", sig)
+		procMu.Lock()
+		defer procMu.Unlock()
+		stopped = true
+		if current != nil {
+			if err := current.Stop(sig, opts.Grace); err != nil {
+				log.Printf("App exited: %v", err)
+			}
+			current = nil
+		}
+	}
+
+	imports := importCache{}
+	imports.scan(projectPath, opts.Exclude, opts.OutDir)
 
 	fmt.Println("Running the project, and watching for changes...")
 
@@ -92,21 +188,8 @@ func WatchAndRebuild(projectPath, projectName string, startApp func(projectPath,
 		log.Printf("Initial watcher setup failed: %v", err)
 	}
 
-	ignorePaths := map[string]bool{
-		"node_modules": true,
-		"dist":         true,
-		".git":         true,
-		"build":        true,
-		".DS_Store":    true,
-	}
-
-	validExts := map[string]bool{
-		".go":     true,
-		".svelte": true,
-		".css":    true,
-		".html":   true,
-		".ts":     true,
-		".js":     true,
+	ignoreFiles := map[string]bool{
+		".DS_Store": true,
 	}
 
 	ignoreExts := map[string]bool{
@@ -115,17 +198,102 @@ func WatchAndRebuild(projectPath, projectName string, startApp func(projectPath,
 		"~":     true,
 	}
 
+	// shouldIgnorePath 忽略排除的目錄底下的檔案與暫存檔
 	shouldIgnorePath := func(path string) bool {
-		for ignorePath := range ignorePaths {
-			if strings.Contains(path, ignorePath) {
+		if opts.OutDir != "" {
+			if rel, err := filepath.Rel(opts.OutDir, path); err == nil && !strings.HasPrefix(rel, "..") {
+				return true
+			}
+		}
+		rel, err := filepath.Rel(projectPath, path)
+		if err != nil {
+			rel = path
+		}
+		for _, part := range strings.Split(filepath.ToSlash(rel), "/") {
+			if slices.Contains(opts.Exclude, part) || ignoreFiles[part] {
 				return true
 			}
 		}
 
 		ext := filepath.Ext(path)
-		return ignoreExts[ext]
+		return ignoreExts[ext] || strings.HasSuffix(path, "~")
 	}
 
+	// rebuild 只執行變更所需的構建階段，成功後替換正在執行的程序
+	rebuild := func(changedPaths []string) {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("Failed to handle file change: %v", r)
+			}
+		}()
+
+		fmt.Printf("\nFiles changed: %s\nRebuilding project...\n", strings.Join(changedPaths, ", "))
+
+		if err := setupWatchers(watcher); err != nil {
+			log.Printf("Failed to reset watchers: %v", err)
+		}
+
+		buildAndSwap(classify(changedPaths, imports))
+	}
+
+	// 構建進行中若又有變更，只標記 dirty，構建結束後再補一次構建
+	var (
+		mu           sync.Mutex
+		rebuilding   bool
+		dirty        bool
+		changedPaths []string
+		initialBuild = true // 第一次構建執行所有階段
+	)
+	requestRebuild := func(paths []string) {
+		mu.Lock()
+		for _, path := range paths {
+			if !slices.Contains(changedPaths, path) {
+				changedPaths = append(changedPaths, path)
+			}
+		}
+		if rebuilding {
+			dirty = true
+			mu.Unlock()
+			return
+		}
+		rebuilding = true
+		mu.Unlock()
+
+		go func() {
+			for {
+				mu.Lock()
+				paths := changedPaths
+				changedPaths = nil
+				dirty = false
+				initial := initialBuild
+				initialBuild = false
+				mu.Unlock()
+
+				if initial {
+					buildAndSwap(fullPlan)
+				} else {
+					rebuild(paths)
+				}
+
+				mu.Lock()
+				if !dirty {
+					rebuilding = false
+					mu.Unlock()
+					return
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	// 在背景執行第一次構建，構建期間也能處理訊號
+	requestRebuild(nil)
+
+	// 編輯器存檔時常會連續觸發多個事件，等待 debounceDelay 沒有新事件後才構建
+	var debounceTimer *time.Timer
+	var pendingPaths []string
+	debounced := make(chan struct{}, 1)
+
 	for {
 		select {
 		case event, ok := <-watcher.Events:
@@ -135,41 +303,32 @@ func WatchAndRebuild(projectPath, projectName string, startApp func(projectPath,
 
 			if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Remove|fsnotify.Rename) != 0 {
 				ext := filepath.Ext(event.Name)
-				if !validExts[ext] && ext != "" {
+				if !slices.Contains(opts.Extensions, ext) && ext != "" {
 					continue
 				}
 
-				if !isRebuilding.CompareAndSwap(false, true) {
-					continue
+				if !slices.Contains(pendingPaths, event.Name) {
+					pendingPaths = append(pendingPaths, event.Name)
 				}
-
-				go func(eventName string) {
-					defer func() {
-						isRebuilding.Store(false)
-						if r := recover(); r != nil {
-							log.Printf("Failed to handle file change: %v", r)
-						}
-					}()
-
-					fmt.Printf("\nFile changed: %s\nRebuilding project...\n", eventName)
-
-					select {
-					case cmd := <-processChannel:
-						currentCmd = cmd
-						if currentCmd != nil && currentCmd.Process != nil {
-							_ = currentCmd.Process.Kill()
-							_ = currentCmd.Wait()
+				if debounceTimer == nil {
+					debounceTimer = time.AfterFunc(debounceDelay, func() {
+						select {
+						case debounced <- struct{}{}:
+						default:
 						}
-					default:
-					}
+					})
+				} else {
+					debounceTimer.Reset(debounceDelay)
+				}
+			}
 
-					if err := setupWatchers(watcher); err != nil {
-						log.Printf("Failed to reset watchers: %v", err)
-					}
+		case <-debounced:
+			requestRebuild(pendingPaths)
+			pendingPaths = nil
 
-					startAndMonitor()
-				}(event.Name)
-			}
+		case sig := <-opts.Signals:
+			shutdown(sig)
+			return
 
 		case err, ok := <-watcher.Errors:
 			if !ok {
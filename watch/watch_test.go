@@ -0,0 +1,148 @@
+package watch
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/TimLai666/golte-cli/devserver"
+	"github.com/TimLai666/golte-cli/proc"
+)
+
+// TestSwapReleasesHeldRequests checks that requests held by the dev server
+// between BeforeSwap and AfterSwap are released however the swap ends.
+func TestSwapReleasesHeldRequests(t *testing.T) {
+	tests := []struct {
+		name string
+		// start is the Start of the options, nil for the default that starts a helper process.
+		start func(projectPath, projectName string, isSveltigo bool) (*proc.Process, error)
+		// shutdown stops watching while the build runs, so the swap is abandoned.
+		shutdown    bool
+		wantStarted bool
+	}{
+		{name: "started", wantStarted: true},
+		{
+			name: "start failure",
+			start: func(string, string, bool) (*proc.Process, error) {
+				return nil, errors.New("exec format error")
+			},
+		},
+		{name: "shutdown", shutdown: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				io.WriteString(w, "ok")
+			}))
+			defer app.Close()
+			server, err := devserver.New(app.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			dev := httptest.NewServer(server.Handler())
+			defer dev.Close()
+			// 測試失敗時仍釋放等待中的請求，dev.Close 才不會等到 hold 逾時
+			defer server.Resume(false)
+
+			buildStarted := make(chan struct{})
+			finishBuild := make(chan struct{})
+			if !tt.shutdown {
+				close(finishBuild)
+			}
+			held := make(chan error, 1)
+			swapped := make(chan bool, 1)
+			signals := make(chan os.Signal, 1)
+			opts := Options{
+				ProjectPath: t.TempDir(),
+				ProjectName: "app",
+				Grace:       time.Second,
+				Signals:     signals,
+				Build: func(string, string, bool, Plan) error {
+					close(buildStarted)
+					<-finishBuild
+					return nil
+				},
+				Start: tt.start,
+				BeforeSwap: func() {
+					server.Hold()
+					go func() {
+						resp, err := http.Get(dev.URL)
+						if err == nil {
+							resp.Body.Close()
+						}
+						held <- err
+					}()
+					select {
+					case err := <-held:
+						t.Errorf("request was not held during the swap: %v", err)
+					case <-time.After(100 * time.Millisecond):
+					}
+				},
+				AfterSwap: func(started bool) {
+					server.Resume(started)
+					swapped <- started
+				},
+			}
+			if opts.Start == nil {
+				opts.Start = func(string, string, bool) (*proc.Process, error) {
+					return proc.Start(helperProcess())
+				}
+			}
+			watching := make(chan struct{})
+			go func() {
+				WatchAndRebuild(opts)
+				close(watching)
+			}()
+
+			<-buildStarted
+			if tt.shutdown {
+				// 構建期間收到訊號，構建完成後不再啟動新程序
+				signals <- syscall.SIGTERM
+				<-watching
+				close(finishBuild)
+			}
+			select {
+			case started := <-swapped:
+				if started != tt.wantStarted {
+					t.Errorf("AfterSwap(%v), want AfterSwap(%v)", started, tt.wantStarted)
+				}
+			case <-time.After(15 * time.Second):
+				t.Fatal("AfterSwap was not called")
+			}
+			select {
+			case err := <-held:
+				if err != nil {
+					t.Errorf("held request failed: %v", err)
+				}
+			case <-time.After(5 * time.Second):
+				t.Error("held request was not released")
+			}
+			if !tt.shutdown {
+				signals <- syscall.SIGTERM
+				<-watching
+			}
+		})
+	}
+}
+
+// helperProcess returns a command running TestHelperProcess, a stand-in for
+// the app that runs until it is stopped.
+func helperProcess() *exec.Cmd {
+	cmd := exec.Command(os.Args[0], "-test.run=^TestHelperProcess$")
+	cmd.Env = append(os.Environ(), "GOLTE_CLI_HELPER_PROCESS=1")
+	return cmd
+}
+
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv("GOLTE_CLI_HELPER_PROCESS") != "1" {
+		return
+	}
+	time.Sleep(time.Minute)
+	os.Exit(0)
+}
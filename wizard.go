@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/TimLai666/golte-cli/config"
+	"github.com/TimLai666/golte-cli/create"
+)
+
+// runNewWizard 以問答方式設定 new 的參數，印出等效的命令列並回傳專案名稱
+func runNewWizard(cmd *cobra.Command, args []string) string {
+	flags := cmd.Flags()
+	check := func(err error) {
+		if err != nil {
+			log.Fatalf("Failed to read answer: %v", err)
+		}
+	}
+	set := func(name, value string) {
+		check(flags.Set(name, value))
+	}
+
+	defaultName := "my-app"
+	if len(args) > 0 {
+		defaultName = args[0]
+	}
+	projectName, err := terminal.Input("Project name", defaultName, create.ValidateProjectName)
+	check(err)
+
+	modulePath, _ := flags.GetString("module")
+	if modulePath == "" {
+		modulePath = create.DefaultModulePath(projectName)
+	}
+	modulePath, err = terminal.Input("Go module path", modulePath, create.ValidateModulePath)
+	check(err)
+	if modulePath != create.DefaultModulePath(projectName) {
+		set("module", modulePath)
+	}
+
+	flavor := config.FlavorGolte
+	if isSveltigo, _ := flags.GetBool("sveltigo"); isSveltigo {
+		flavor = config.FlavorSveltigo
+	}
+	flavor, err = terminal.Select("Flavor", []string{config.FlavorGolte, config.FlavorSveltigo}, flavor)
+	check(err)
+	set("sveltigo", fmt.Sprint(flavor == config.FlavorSveltigo))
+
+	router, err := create.NormalizeRouter(flags.Lookup("router").Value.String())
+	if err != nil {
+		router = create.DefaultRouter
+	}
+	router, err = terminal.Select("Router", create.Routers, router)
+	check(err)
+	set("router", router)
+
+	here, _ := flags.GetBool("here")
+	here, err = terminal.Confirm(fmt.Sprintf("Create the project in the current directory instead of ./%s?", projectName), here)
+	check(err)
+	set("here", fmt.Sprint(here))
+
+	typeScript, _ := flags.GetBool("typescript")
+	typeScript, err = terminal.Confirm("Use TypeScript?", typeScript)
+	check(err)
+	set("typescript", fmt.Sprint(typeScript))
+
+	css, err := create.NormalizeCSS(flags.Lookup("css").Value.String())
+	if err != nil {
+		css = "none"
+	}
+	css, err = terminal.Select("CSS framework", create.CSSFrameworks, css)
+	check(err)
+	set("css", css)
+
+	features, _ := flags.GetStringSlice("feature")
+	features, err = terminal.MultiSelect("Features", create.Features, features)
+	check(err)
+	// Set 會附加到已指定的 --feature，因此直接取代整個清單
+	featureFlag := flags.Lookup("feature")
+	check(featureFlag.Value.(pflag.SliceValue).Replace(features))
+	featureFlag.Changed = true
+
+	fmt.Printf("\nEquivalent command:\n  %s\n\n", equivalentCommand(cmd, projectName))
+	ok, err := terminal.Confirm("Create the project?", true)
+	check(err)
+	if !ok {
+		os.Exit(0)
+	}
+	return projectName
+}
+
+// equivalentCommand 回傳不需互動、產生相同專案的命令列，只包含與預設值不同的參數
+func equivalentCommand(cmd *cobra.Command, projectName string) string {
+	parts := []string{cmd.CommandPath(), shellQuote(projectName)}
+	cmd.Flags().Visit(func(flag *pflag.Flag) {
+		if flag.Name == "interactive" || flag.Name == "yes" {
+			return
+		}
+		switch value := flag.Value.(type) {
+		case pflag.SliceValue:
+			values := value.GetSlice()
+			if len(values) == 0 {
+				return
+			}
+			if flag.Value.Type() == "stringArray" {
+				for _, v := range values {
+					parts = append(parts, "--"+flag.Name, shellQuote(v))
+				}
+				return
+			}
+			parts = append(parts, "--"+flag.Name+"="+shellQuote(strings.Join(values, ",")))
+		default:
+			if flag.Value.String() == flag.DefValue {
+				return
+			}
+			if flag.Value.Type() == "bool" && flag.Value.String() == "true" {
+				parts = append(parts, "--"+flag.Name)
+			} else {
+				parts = append(parts, "--"+flag.Name+"="+shellQuote(flag.Value.String()))
+			}
+		}
+	})
+	return strings.Join(append(parts, "--yes"), " ")
+}
+
+// shellQuote 在值包含特殊字元時加上單引號
+func shellQuote(value string) string {
+	if value != "" && strings.IndexFunc(value, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./=:,@+", r))
+	}) < 0 {
+		return value
+	}
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}